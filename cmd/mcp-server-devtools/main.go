@@ -16,6 +16,9 @@ var (
 )
 
 func main() {
+	// Watches dir:// resources; created below once the server exists
+	var dirWatchers *tools.DirWatchers
+
 	// Create MCP server using the official SDK
 	server := mcp.NewServer(
 		&mcp.Implementation{
@@ -24,6 +27,12 @@ func main() {
 		},
 		&mcp.ServerOptions{
 			Instructions: "A collection of useful developer tools including color conversion and network information.",
+			SubscribeHandler: func(ctx context.Context, req *mcp.SubscribeRequest) error {
+				return tools.SubscribeResource(ctx, req, dirWatchers)
+			},
+			UnsubscribeHandler: func(ctx context.Context, req *mcp.UnsubscribeRequest) error {
+				return tools.UnsubscribeResource(ctx, req, dirWatchers)
+			},
 		},
 	)
 
@@ -39,16 +48,175 @@ func main() {
 		Description: "Get the current computer's IP addresses, including all network interfaces and the primary IP address",
 	}, tools.GetIPAddress)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "subnet_calc",
+		Description: "Calculate subnet details from a CIDR or IP and mask (network, broadcast, host range, host count, wildcard mask, IPv6 expanded/compressed forms), test address membership, split a subnet, aggregate CIDRs into supernets and detect overlaps with this machine's interface subnets.",
+	}, tools.SubnetCalc)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_listening_ports",
+		Description: "List listening TCP and UDP sockets with the PID, name and command line of the owning process. Can be filtered by port, process or protocol (currently supports Linux only).",
+	}, tools.ListListeningPorts)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_connections",
+		Description: "List active TCP connections with their state, local and remote endpoints and owning process, aggregated by remote host and by process. Can be filtered by state, process, remote host or port (currently supports Linux only).",
+	}, tools.ListConnections)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "check_port_availability",
+		Description: "Check whether a TCP/UDP port or port range is free on a local address (IPv4 and IPv6), or find a number of free ports in a range, skipping privileged and kernel-reserved ports.",
+	}, tools.CheckPortAvailability)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "free_port",
+		Description: "Stop the process holding a port. Asks the user for confirmation showing the PID and command line, then sends SIGTERM followed by SIGKILL after a timeout. Refuses PID 1 and processes owned by other users (currently supports Linux only).",
+	}, tools.FreePort)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "dns_lookup",
+		Description: "Resolve a host name (A, AAAA, CNAME, MX, TXT, SRV) or IP address (PTR) using the system resolver or a specific DNS server, and show matching hosts file entries and the nameservers and search domains from resolv.conf.",
+	}, tools.DNSLookup)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "probe_endpoint",
+		Description: "Check whether a host:port accepts TCP connections and measure connect latency, optionally performing an HTTP(S) request and returning status, headers, a timing breakdown (DNS, connect, TLS, TTFB) and a truncated body.",
+	}, tools.ProbeEndpoint)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "proxy_settings",
+		Description: "Report effective proxy settings (HTTP_PROXY, HTTPS_PROXY, NO_PROXY in all casings, git and npm proxy config), evaluate which proxy Go's http.ProxyFromEnvironment would use for a URL and whether NO_PROXY excludes it, and flag common misconfigurations.",
+	}, tools.ProxySettings)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_host_mappings",
+		Description: "List the IP to host name mappings of the hosts file, marking the ones inside the '# BEGIN mcp-devtools' block managed by this server.",
+	}, tools.ListHostMappings)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "add_host_mapping",
+		Description: "Map host names to an IP address inside the '# BEGIN mcp-devtools' / '# END mcp-devtools' block of the hosts file. Asks the user for confirmation, writes atomically and keeps a backup.",
+	}, tools.AddHostMapping)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "remove_host_mapping",
+		Description: "Remove host names from the '# BEGIN mcp-devtools' / '# END mcp-devtools' block of the hosts file. Asks the user for confirmation, writes atomically and keeps a backup.",
+	}, tools.RemoveHostMapping)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "get_current_time",
-		Description: "Get the current server time in RFC1123 format",
+		Description: "Get the current time in one or more IANA time zones (defaults to the server's zone) with structured fields: ISO string, Unix epoch, zone name, UTC offset, DST flag, ISO week and day of year. The formatted field supports RFC3339, RFC1123, Unix seconds/millis, Go layouts and strftime patterns.",
 	}, tools.GetCurrentTime)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "time_convert",
+		Description: "Parse a timestamp in many formats (RFC3339, RFC1123, Unix seconds/ms/µs/ns auto-detected, log formats, natural forms like '2025-03-01 14:00 PST') and convert it into one or more IANA time zones, explaining ambiguous or non-existent local times around DST transitions.",
+	}, tools.TimeConvert)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "cron_schedule",
+		Description: "Validate and explain a cron expression (5-field, 6-field with seconds, Quartz style with ?, L, W and #, or macros like @daily) in plain English and list its next or previous fire times in a time zone, handling DST transitions.",
+	}, tools.CronSchedule)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "date_math",
+		Description: "Date arithmetic: add or subtract durations (calendar units, ISO 8601 like P1Y2M3DT4H, or '3 months 2 days') to dates, compute the difference between two dates in multiple units, count business days with configurable weekends and holidays, and humanize durations ('3 days ago').",
+	}, tools.DateMath)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "translate_time_format",
+		Description: "Translate a date format pattern between Go reference layouts, C strftime, Python, Java DateTimeFormatter and moment.js/day.js, with an example rendered from the current time and notes about approximations and portability.",
+	}, tools.TranslateTimeFormat)
+
+	// Timers notify subscribers of timer:// resources, so they need the server
+	timers := tools.NewTimers(server)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "start_timer",
+		Description: "Start a named countdown timer, reminder (fires at a given time, optionally repeating) or stopwatch. When a timer fires the server sends a logging notification and a resource update for the timer's uri, which is unique to the session.",
+	}, timers.Start)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_timers",
+		Description: "List the active timers and stopwatches of this session with elapsed and remaining time.",
+	}, timers.List)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "cancel_timer",
+		Description: "Cancel a timer or stop a stopwatch by name and return its final state.",
+	}, timers.Cancel)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "timer",
+		URITemplate: "timer://{session}/{name}",
+		Description: "State of a timer or stopwatch started with start_timer, at the uri it returned. Subscribe to be notified when it fires; only the session that started the timer can read it or is notified.",
+		MIMEType:    "application/json",
+	}, timers.ReadResource)
+
+	dirWatchers = tools.NewDirWatchers(server)
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "directory",
+		URITemplate: "dir://{+path}",
+		Description: "Entries and recent changes of a directory, e.g. dir://~/Downloads or dir:///tmp/build. Subscribe to be notified, debounced, when files are added, modified or removed; the directory is watched with inotify on Linux and by polling elsewhere.",
+		MIMEType:    "application/json",
+	}, dirWatchers.ReadResource)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "list_old_downloads",
-		Description: "List files in the Download directory that haven't been modified in a long time.",
+		Description: "List files in the Downloads directory (or other directories, optionally recursive) that haven't been modified or accessed for a configurable time, with glob and size filters, sorting and pagination.",
 	}, tools.ListOldDownloads)
 
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_user_directories",
+		Description: "List the user's Desktop, Documents, Downloads, Music, Pictures, Videos, Templates and Public directories, resolved from XDG user-dirs settings on Linux, the shell folders registry on Windows, or the OS defaults.",
+	}, tools.ListUserDirectories)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "trash_files",
+		Description: "Move files or directories to the freedesktop.org trash (Linux). A call with paths only previews what would be trashed and returns a preview_id; calling again with that preview_id moves the previewed items after the user confirms.",
+	}, tools.TrashFiles)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_trash",
+		Description: "List the items in the user's trash directories (Linux), with their original paths, deletion dates and sizes.",
+	}, tools.ListTrash)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "restore_from_trash",
+		Description: "Restore items from the trash (Linux) to the location they were deleted from, by trash name or original path. With overwrite, existing files at those locations are moved to the trash after confirmation instead of being deleted.",
+	}, tools.RestoreFromTrash)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "find_duplicates",
+		Description: "Find files with identical contents in the Downloads directory or other directories. Files are grouped by size, then by a hash of their first and last bytes, then by full SHA-256; reports each duplicate group with the bytes wasted and a suggested file to keep. Sends progress notifications when the client provides a progress token.",
+	}, tools.FindDuplicates)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "disk_usage",
+		Description: "Analyze what takes up space below a directory: total size, file and directory counts, the largest directories and files, and a breakdown by extension and file category. Supports .gitignore-style excludes, stays on one filesystem unless asked otherwise, and handles symlink loops.",
+	}, tools.DiskUsage)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "list_filesystems",
+		Description: "List mounted filesystems with their device, type, mount options, total/used/free space and inode usage, flagging those above a usage threshold. Pass a path to find out which filesystem it is on and how full that filesystem is.",
+	}, tools.ListFilesystems)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "organize_directory",
+		Description: "Sort the files of a directory (the Downloads directory by default) into category folders such as Installers, Archives, Images, Documents and Videos, classifying by extension and file contents. Returns the planned moves unless apply is set; applied moves ask for confirmation, never overwrite files, and write an undo journal that can be passed back as undo_journal.",
+	}, tools.OrganizeDirectory)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "archive_files",
+		Description: "Pack files into a .zip or .tar.gz archive instead of deleting them. Select files by path or with the same criteria as list_old_downloads. Timestamps are preserved, a .mcp-devtools/MANIFEST.json with SHA-256 checksums is stored in the archive, an existing archive is never overwritten, and the archive is read back and verified; originals are only removed when requested, after verification and confirmation.",
+	}, tools.ArchiveFiles)
+
+	mcp.AddTool(server, &mcp.Tool{
+		Name:        "checksum",
+		Description: "Compute MD5, SHA-1, SHA-256, SHA-512, BLAKE2b or BLAKE3 hashes of a file or directory tree, streaming with progress. Verify a download against an expected hash or a checksum file such as SHA256SUMS or B2SUMS; mismatched, missing and unreadable files are listed with the actual and expected hash.",
+	}, tools.Checksum)
+
 	mcp.AddTool(server, &mcp.Tool{
 		Name:        "list_installed_apps",
 		Description: "List installed applications on the system (currently supports macOS only).",
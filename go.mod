@@ -7,10 +7,15 @@ toolchain go1.24.11
 require (
 	github.com/lucasb-eyer/go-colorful v1.3.0
 	github.com/modelcontextprotocol/go-sdk v1.1.0
+	golang.org/x/crypto v0.41.0
+	golang.org/x/net v0.43.0
+	lukechampine.com/blake3 v1.4.1
 )
 
 require (
 	github.com/google/jsonschema-go v0.3.0 // indirect
+	github.com/klauspost/cpuid/v2 v2.0.12 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
 	golang.org/x/oauth2 v0.30.0 // indirect
+	golang.org/x/sys v0.35.0 // indirect
 )
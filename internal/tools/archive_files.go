@@ -0,0 +1,545 @@
+package tools
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// archiveManifestName is the manifest stored as the last entry of every archive.
+// It lives in a reserved directory so it cannot clash with an archived file.
+const archiveManifestName = ".mcp-devtools/MANIFEST.json"
+
+type archiveFilesInput struct {
+	Paths           []string               `json:"paths,omitempty" jsonschema:"Files or directories to archive; directories are added recursively"`
+	Criteria        *listOldDownloadsInput `json:"criteria,omitempty" jsonschema:"Select files with the same criteria as list_old_downloads instead of listing paths"`
+	Output          string                 `json:"output,omitempty" jsonschema:"Archive to create, ending in .zip, .tar.gz or .tgz. Defaults to archive-<date>.zip next to the files"`
+	Format          string                 `json:"format,omitempty" jsonschema:"'zip' or 'tar.gz'; inferred from output when omitted, defaults to zip"`
+	RemoveOriginals bool                   `json:"remove_originals,omitempty" jsonschema:"Delete the archived files after the archive is verified and the user confirms"`
+}
+
+type archiveManifestEntry struct {
+	Name         string    `json:"name" jsonschema:"Path inside the archive"`
+	OriginalPath string    `json:"original_path" jsonschema:"Path the file was archived from, with symlinks resolved; this is the file remove_originals deletes"`
+	Size         int64     `json:"size" jsonschema:"Size in bytes"`
+	Modified     time.Time `json:"modified" jsonschema:"Last modify time of the file"`
+	SHA256       string    `json:"sha256" jsonschema:"SHA-256 of the file contents"`
+}
+
+type archiveManifest struct {
+	Created time.Time              `json:"created"`
+	BaseDir string                 `json:"base_dir"`
+	Files   []archiveManifestEntry `json:"files"`
+}
+
+type archiveFilesOutput struct {
+	Archive          string                 `json:"archive" jsonschema:"Path of the created archive"`
+	Format           string                 `json:"format" jsonschema:"Archive format"`
+	ArchiveSize      int64                  `json:"archive_size" jsonschema:"Size of the archive in bytes"`
+	ArchiveSHA256    string                 `json:"archive_sha256" jsonschema:"SHA-256 of the archive file"`
+	OriginalSize     int64                  `json:"original_size" jsonschema:"Combined size of the archived files in bytes"`
+	Files            []archiveManifestEntry `json:"files" jsonschema:"Archived files, as recorded in the manifest stored in the archive"`
+	Verified         bool                   `json:"verified" jsonschema:"Whether every file was read back from the archive with a matching checksum"`
+	OriginalsRemoved int                    `json:"originals_removed" jsonschema:"Number of original files deleted"`
+	Cancelled        bool                   `json:"cancelled" jsonschema:"Whether the user declined removing the originals"`
+	Errors           []string               `json:"errors,omitempty" jsonschema:"Problems verifying the archive or removing originals"`
+}
+
+// archiveFormat works out the format from the input and output name
+func archiveFormat(format, output string) (string, error) {
+	switch strings.ToLower(format) {
+	case "zip":
+		return "zip", nil
+	case "tar.gz", "tgz", "targz":
+		return "tar.gz", nil
+	case "":
+	default:
+		return "", fmt.Errorf("unknown format '%s', expected 'zip' or 'tar.gz'", format)
+	}
+
+	lower := strings.ToLower(output)
+	if strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz") {
+		return "tar.gz", nil
+	}
+	return "zip", nil
+}
+
+// commonDir returns the deepest directory containing all paths
+func commonDir(paths []string) string {
+	base := filepath.Dir(paths[0])
+	for _, p := range paths[1:] {
+		for base != filepath.Dir(base) && !strings.HasPrefix(p, base+string(filepath.Separator)) {
+			base = filepath.Dir(base)
+		}
+	}
+	return base
+}
+
+// collectArchivePaths expands explicit paths into the regular files below them.
+// Symlinks named explicitly are followed; symlinks found while walking are not.
+func collectArchivePaths(ctx context.Context, paths []string) ([]string, error) {
+	var files []string
+	seen := make(map[string]bool)
+
+	for _, p := range paths {
+		path, err := expandHome(p)
+		if err != nil {
+			return nil, err
+		}
+		if path, err = filepath.Abs(path); err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", p, err)
+		}
+
+		// WalkDir does not follow a symlink passed as its root
+		root := path
+		if info, err := os.Lstat(path); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			if root, err = filepath.EvalSymlinks(path); err != nil {
+				return nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+			}
+		}
+
+		err = filepath.WalkDir(root, func(file string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			// Files below a followed symlink keep the path they were asked for by
+			if root != path {
+				rel, err := filepath.Rel(root, file)
+				if err != nil {
+					return err
+				}
+				file = filepath.Join(path, rel)
+			}
+			if !seen[file] {
+				seen[file] = true
+				files = append(files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+
+	return files, nil
+}
+
+// archiveWriter adds files to a zip or tar.gz archive
+type archiveWriter interface {
+	add(name string, info os.FileInfo) (io.Writer, error)
+	Close() error
+}
+
+type zipArchiveWriter struct{ zw *zip.Writer }
+
+func (w zipArchiveWriter) add(name string, info os.FileInfo) (io.Writer, error) {
+	hdr, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return nil, err
+	}
+	hdr.Name = name
+	hdr.Method = zip.Deflate
+	return w.zw.CreateHeader(hdr)
+}
+
+func (w zipArchiveWriter) Close() error { return w.zw.Close() }
+
+type tarArchiveWriter struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (w tarArchiveWriter) add(name string, info os.FileInfo) (io.Writer, error) {
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return nil, err
+	}
+	hdr.Name = name
+	// PAX keeps sub-second modification times
+	hdr.Format = tar.FormatPAX
+	if err := w.tw.WriteHeader(hdr); err != nil {
+		return nil, err
+	}
+	return w.tw, nil
+}
+
+func (w tarArchiveWriter) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+// manifestFileInfo describes the in-memory manifest entry
+type manifestFileInfo struct {
+	size    int64
+	modTime time.Time
+}
+
+func (m manifestFileInfo) Name() string       { return filepath.Base(archiveManifestName) }
+func (m manifestFileInfo) Size() int64        { return m.size }
+func (m manifestFileInfo) Mode() os.FileMode  { return 0o644 }
+func (m manifestFileInfo) ModTime() time.Time { return m.modTime }
+func (m manifestFileInfo) IsDir() bool        { return false }
+func (m manifestFileInfo) Sys() any           { return nil }
+
+// writeArchive writes files into out, returning the manifest
+func writeArchive(ctx context.Context, out io.Writer, format string, files []string, base string, progress *progressReporter) (*archiveManifest, error) {
+	var w archiveWriter
+	if format == "zip" {
+		w = zipArchiveWriter{zip.NewWriter(out)}
+	} else {
+		gz := gzip.NewWriter(out)
+		w = tarArchiveWriter{gz, tar.NewWriter(gz)}
+	}
+
+	manifest := &archiveManifest{Created: time.Now(), BaseDir: base, Files: []archiveManifestEntry{}}
+
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		// Names keep the symlinks the files were asked for by, but the manifest
+		// records the real file, which is what remove_originals deletes
+		original, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return nil, err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return nil, err
+		}
+		name := filepath.ToSlash(rel)
+		if name == archiveManifestName {
+			return nil, fmt.Errorf("%s cannot be archived, %s is reserved for the manifest", path, archiveManifestName)
+		}
+
+		entryWriter, err := w.add(name, info)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s: %w", path, err)
+		}
+
+		f, err := os.Open(original)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.New()
+		n, err := io.Copy(io.MultiWriter(entryWriter, h), ctxReader{ctx, f})
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s: %w", path, err)
+		}
+		if n != info.Size() {
+			return nil, fmt.Errorf("%s changed while it was being archived", path)
+		}
+
+		manifest.Files = append(manifest.Files, archiveManifestEntry{
+			Name:         name,
+			OriginalPath: original,
+			Size:         n,
+			Modified:     info.ModTime(),
+			SHA256:       hex.EncodeToString(h.Sum(nil)),
+		})
+		progress.add(float64(n), "Archiving "+name)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	entryWriter, err := w.add(archiveManifestName, manifestFileInfo{size: int64(len(data)), modTime: manifest.Created})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add manifest: %w", err)
+	}
+	if _, err := entryWriter.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to add manifest: %w", err)
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// verifyArchive reads every entry back and compares it with the manifest
+func verifyArchive(ctx context.Context, path, format string, manifest *archiveManifest) []string {
+	expected := make(map[string]archiveManifestEntry, len(manifest.Files))
+	for _, f := range manifest.Files {
+		expected[f.Name] = f
+	}
+
+	var problems []string
+	seen := make(map[string]bool)
+
+	check := func(name string, modified time.Time, r io.Reader) {
+		if name == archiveManifestName {
+			return
+		}
+		seen[name] = true
+		want, ok := expected[name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unexpected entry %s", name))
+			return
+		}
+		h := sha256.New()
+		n, err := io.Copy(h, ctxReader{ctx, r})
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s: %v", name, err))
+		case n != want.Size:
+			problems = append(problems, fmt.Sprintf("%s: size %d, expected %d", name, n, want.Size))
+		case hex.EncodeToString(h.Sum(nil)) != want.SHA256:
+			problems = append(problems, fmt.Sprintf("%s: checksum mismatch", name))
+		case modified.Sub(want.Modified).Abs() > 2*time.Second:
+			// zip stores local times with 2 second precision in its legacy field
+			problems = append(problems, fmt.Sprintf("%s: modification time %s, expected %s", name, modified, want.Modified))
+		}
+	}
+
+	if format == "zip" {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return []string{fmt.Sprintf("failed to open archive: %v", err)}
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", f.Name, err))
+				continue
+			}
+			check(f.Name, f.Modified, rc)
+			rc.Close()
+		}
+	} else {
+		file, err := os.Open(path)
+		if err != nil {
+			return []string{fmt.Sprintf("failed to open archive: %v", err)}
+		}
+		defer file.Close()
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return []string{fmt.Sprintf("failed to open archive: %v", err)}
+		}
+		tr := tar.NewReader(gz)
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("failed to read archive: %v", err))
+				break
+			}
+			check(hdr.Name, hdr.ModTime, tr)
+		}
+	}
+
+	for _, f := range manifest.Files {
+		if !seen[f.Name] {
+			problems = append(problems, fmt.Sprintf("%s is missing from the archive", f.Name))
+		}
+	}
+	return problems
+}
+
+// publishArchive moves the finished temporary archive to its final name without
+// replacing a file created there in the meantime. Hard links fail if the name is
+// taken; filesystems without hard links fall back to checking just before renaming.
+func publishArchive(tmp, output string) error {
+	err := os.Link(tmp, output)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, fs.ErrExist):
+		return fmt.Errorf("%s already exists", output)
+	}
+
+	if _, err := os.Lstat(output); err == nil {
+		return fmt.Errorf("%s already exists", output)
+	}
+	if err := os.Rename(tmp, output); err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	return nil
+}
+
+// ArchiveFiles packs files into a zip or tar.gz archive with a checksum manifest
+func ArchiveFiles(ctx context.Context, req *mcp.CallToolRequest, input archiveFilesInput) (*mcp.CallToolResult, *archiveFilesOutput, error) {
+	if (len(input.Paths) == 0) == (input.Criteria == nil) {
+		return nil, nil, fmt.Errorf("pass either paths or criteria")
+	}
+
+	var files []string
+	if input.Criteria != nil {
+		scan, err := scanOldFiles(ctx, *input.Criteria)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, f := range scan.files {
+			files = append(files, f.Path)
+		}
+	} else {
+		var err error
+		if files, err = collectArchivePaths(ctx, input.Paths); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(files) == 0 {
+		return nil, nil, fmt.Errorf("no files to archive")
+	}
+	sort.Strings(files)
+	base := commonDir(files)
+
+	format, err := archiveFormat(input.Format, input.Output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := input.Output
+	if output == "" {
+		ext := ".zip"
+		if format == "tar.gz" {
+			ext = ".tar.gz"
+		}
+		output = filepath.Join(base, "archive-"+time.Now().Format("20060102-150405")+ext)
+	}
+	if output, err = expandHome(output); err != nil {
+		return nil, nil, err
+	}
+	if output, err = filepath.Abs(output); err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve %s: %w", input.Output, err)
+	}
+	if _, err := os.Lstat(output); err == nil {
+		return nil, nil, fmt.Errorf("%s already exists", output)
+	}
+	// Fail early; publishArchive checks again when the archive is moved into place
+
+	// Never put the archive into itself
+	kept := files[:0]
+	for _, f := range files {
+		if f != output {
+			kept = append(kept, f)
+		}
+	}
+	files = kept
+
+	progress := newProgressReporter(ctx, req)
+	var total int64
+	for _, f := range files {
+		if info, err := os.Stat(f); err == nil {
+			total += info.Size()
+		}
+	}
+	progress.setTotal(float64(total), fmt.Sprintf("Archiving %d files", len(files)))
+
+	// Write to a temporary file so a failed run leaves no partial archive behind
+	tmp, err := os.CreateTemp(filepath.Dir(output), "."+filepath.Base(output)+".tmp-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	archiveHash := sha256.New()
+	counter := &countingWriter{}
+	manifest, err := writeArchive(ctx, io.MultiWriter(tmp, archiveHash, counter), format, files, base, progress)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := publishArchive(tmp.Name(), output); err != nil {
+		return nil, nil, err
+	}
+
+	result := &archiveFilesOutput{
+		Archive:       output,
+		Format:        format,
+		ArchiveSize:   counter.n,
+		ArchiveSHA256: hex.EncodeToString(archiveHash.Sum(nil)),
+		Files:         manifest.Files,
+	}
+	for _, f := range manifest.Files {
+		result.OriginalSize += f.Size
+	}
+
+	result.Errors = verifyArchive(ctx, output, format, manifest)
+	result.Verified = len(result.Errors) == 0
+
+	if !input.RemoveOriginals || !result.Verified {
+		return nil, result, nil
+	}
+
+	originals := make(map[string]bool)
+	for _, f := range manifest.Files {
+		originals[f.OriginalPath] = true
+	}
+	confirmed, err := confirmAction(ctx, req, fmt.Sprintf("The archive %s was verified. Delete the %d original file(s) (%d bytes)? Symlinked files are deleted at their targets.", output, len(originals), result.OriginalSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !confirmed {
+		result.Cancelled = true
+		return nil, result, nil
+	}
+
+	removed := make(map[string]bool)
+	for _, f := range manifest.Files {
+		// A file can be archived once more through a symlink to it
+		if removed[f.OriginalPath] {
+			continue
+		}
+		// Keep files that changed after they were archived
+		info, err := os.Stat(f.OriginalPath)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		if info.Size() != f.Size || !info.ModTime().Equal(f.Modified) {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s changed after it was archived, not removed", f.OriginalPath))
+			continue
+		}
+		if err := os.Remove(f.OriginalPath); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			continue
+		}
+		removed[f.OriginalPath] = true
+		result.OriginalsRemoved++
+	}
+
+	return nil, result, nil
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct{ n int64 }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// privilegedPortMax is the highest port that requires elevated privileges to bind on Unix
+	privilegedPortMax = 1023
+	// maxPortRangeReport limits how many ports are reported when checking a range
+	maxPortRangeReport = 1024
+)
+
+type checkPortAvailabilityInput struct {
+	Protocol          string `json:"protocol,omitempty" jsonschema:"Protocol to check ('tcp' or 'udp'), defaults to 'tcp'"`
+	Address           string `json:"address,omitempty" jsonschema:"Local address to bind, e.g. '127.0.0.1' or '::1'. Defaults to the IPv4 and IPv6 wildcard addresses"`
+	Port              int    `json:"port,omitempty" jsonschema:"Port to check, or the first port of the range"`
+	EndPort           int    `json:"end_port,omitempty" jsonschema:"Last port of the range to check or search (inclusive)"`
+	Count             int    `json:"count,omitempty" jsonschema:"Find this many free ports in the range instead of reporting every port"`
+	IncludePrivileged bool   `json:"include_privileged,omitempty" jsonschema:"Also consider privileged ports (below 1024) and kernel-reserved ports when searching for free ports"`
+}
+
+type portStatus struct {
+	Port       int    `json:"port" jsonschema:"Port number"`
+	Free       bool   `json:"free" jsonschema:"Whether the port can be bound on every checked address"`
+	Privileged bool   `json:"privileged,omitempty" jsonschema:"Whether binding the port requires elevated privileges"`
+	Reserved   bool   `json:"reserved,omitempty" jsonschema:"Whether the port is in the kernel's reserved port list"`
+	Reason     string `json:"reason,omitempty" jsonschema:"Why the port could not be bound"`
+}
+
+type checkPortAvailabilityOutput struct {
+	Protocol  string       `json:"protocol" jsonschema:"Protocol that was checked"`
+	Addresses []string     `json:"addresses" jsonschema:"Local addresses the ports were bound on"`
+	Ports     []portStatus `json:"ports" jsonschema:"Status of each checked port, only reported when count is not given"`
+	FreePorts []int        `json:"free_ports" jsonschema:"Free ports found"`
+}
+
+// portCheckAddresses returns the addresses a port has to be bound on, each
+// paired with the network name for its IP family
+func portCheckAddresses(protocol, address string) ([][2]string, error) {
+	if address == "" {
+		addrs := [][2]string{{protocol + "4", "0.0.0.0"}}
+		if hasIPv6() {
+			addrs = append(addrs, [2]string{protocol + "6", "::"})
+		}
+		return addrs, nil
+	}
+
+	ip := net.ParseIP(strings.Trim(address, "[]"))
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address '%s'", address)
+	}
+
+	if ip.To4() != nil {
+		return [][2]string{{protocol + "4", ip.String()}}, nil
+	}
+
+	return [][2]string{{protocol + "6", ip.String()}}, nil
+}
+
+// hasIPv6 reports whether the IPv6 loopback can be bound
+func hasIPv6() bool {
+	l, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		return false
+	}
+	l.Close()
+	return true
+}
+
+// bindPort tries to bind the port and releases it straight away
+func bindPort(ctx context.Context, network, address string, port int) error {
+	var lc net.ListenConfig
+	hostPort := net.JoinHostPort(address, strconv.Itoa(port))
+
+	if strings.HasPrefix(network, "udp") {
+		conn, err := lc.ListenPacket(ctx, network, hostPort)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	l, err := lc.Listen(ctx, network, hostPort)
+	if err != nil {
+		return err
+	}
+	return l.Close()
+}
+
+// bindErrorReason turns a bind error into a short human readable reason
+func bindErrorReason(err error) string {
+	switch {
+	case errors.Is(err, syscall.EADDRINUSE):
+		return "address already in use"
+	case errors.Is(err, syscall.EACCES), errors.Is(err, os.ErrPermission):
+		return "permission denied"
+	case errors.Is(err, syscall.EADDRNOTAVAIL):
+		return "address not available on this machine"
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Err != nil {
+		return opErr.Err.Error()
+	}
+
+	return err.Error()
+}
+
+// isPrivilegedPort reports whether binding the port requires elevated privileges
+func isPrivilegedPort(port int) bool {
+	return runtime.GOOS != "windows" && port <= privilegedPortMax
+}
+
+// reservedPorts reads the ports the Linux kernel keeps out of ephemeral allocation
+func reservedPorts() map[int]bool {
+	reserved := make(map[int]bool)
+
+	data, err := os.ReadFile(filepath.Join(procRoot, "sys", "net", "ipv4", "ip_local_reserved_ports"))
+	if err != nil {
+		return reserved
+	}
+
+	for _, part := range strings.Split(strings.TrimSpace(string(data)), ",") {
+		if part == "" {
+			continue
+		}
+
+		start, end, _ := strings.Cut(part, "-")
+		lo, err := strconv.Atoi(start)
+		if err != nil {
+			continue
+		}
+		hi := lo
+		if end != "" {
+			if hi, err = strconv.Atoi(end); err != nil {
+				continue
+			}
+		}
+
+		for p := lo; p <= hi; p++ {
+			reserved[p] = true
+		}
+	}
+
+	return reserved
+}
+
+// CheckPortAvailability checks whether ports are free to bind and finds free ports in a range
+func CheckPortAvailability(ctx context.Context, req *mcp.CallToolRequest, input checkPortAvailabilityInput) (*mcp.CallToolResult, *checkPortAvailabilityOutput, error) {
+	protocol := strings.ToLower(input.Protocol)
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	if protocol != "tcp" && protocol != "udp" {
+		return nil, nil, fmt.Errorf("unsupported protocol '%s', expected 'tcp' or 'udp'", input.Protocol)
+	}
+
+	if input.Count < 0 {
+		return nil, nil, fmt.Errorf("count must not be negative")
+	}
+
+	start, end := input.Port, input.EndPort
+	if start == 0 {
+		if input.Count == 0 {
+			return nil, nil, fmt.Errorf("either port or count must be given")
+		}
+		start = privilegedPortMax + 1
+	}
+	if end == 0 {
+		end = start
+		if input.Count > 0 {
+			end = 65535
+		}
+	}
+	if start < 1 || end > 65535 || start > end {
+		return nil, nil, fmt.Errorf("invalid port range %d-%d", start, end)
+	}
+	if input.Count == 0 && end-start+1 > maxPortRangeReport {
+		return nil, nil, fmt.Errorf("port range is too large, at most %d ports can be checked at once", maxPortRangeReport)
+	}
+
+	addrs, err := portCheckAddresses(protocol, input.Address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reserved := reservedPorts()
+
+	output := &checkPortAvailabilityOutput{
+		Protocol:  protocol,
+		Addresses: []string{},
+		Ports:     []portStatus{},
+		FreePorts: []int{},
+	}
+
+	for _, addr := range addrs {
+		output.Addresses = append(output.Addresses, addr[1])
+	}
+
+	for port := start; port <= end; port++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+
+		status := portStatus{
+			Port:       port,
+			Free:       true,
+			Privileged: isPrivilegedPort(port),
+			Reserved:   reserved[port],
+		}
+
+		// When searching, skip ports that a normal service should not grab
+		if input.Count > 0 && !input.IncludePrivileged && (status.Privileged || status.Reserved) {
+			continue
+		}
+
+		for _, addr := range addrs {
+			if err := bindPort(ctx, addr[0], addr[1], port); err != nil {
+				status.Free = false
+				status.Reason = bindErrorReason(err)
+				break
+			}
+		}
+
+		if status.Free {
+			output.FreePorts = append(output.FreePorts, port)
+		}
+
+		if input.Count == 0 {
+			output.Ports = append(output.Ports, status)
+		} else if len(output.FreePorts) >= input.Count {
+			break
+		}
+	}
+
+	if input.Count > 0 && len(output.FreePorts) < input.Count {
+		return nil, nil, fmt.Errorf("only found %d of %d free ports in range %d-%d", len(output.FreePorts), input.Count, start, end)
+	}
+
+	return nil, output, nil
+}
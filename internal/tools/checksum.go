@@ -0,0 +1,494 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/crypto/blake2b"
+	"lukechampine.com/blake3"
+)
+
+// checksumAlgorithms are the supported hash functions by canonical name
+var checksumAlgorithms = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+	// b2sum's default BLAKE2b-512
+	"blake2b": func() hash.Hash {
+		h, _ := blake2b.New512(nil) // only fails for keys longer than 64 bytes
+		return h
+	},
+	// b3sum's default 256-bit output
+	"blake3": func() hash.Hash { return blake3.New(32, nil) },
+}
+
+// checksumAlgorithmOrder is the order algorithms are listed and detected in
+var checksumAlgorithmOrder = []string{"md5", "sha1", "sha256", "sha512", "blake2b", "blake3"}
+
+// checksumAlgorithmAliases maps other spellings, after removing '-' and '_', to canonical names
+var checksumAlgorithmAliases = map[string]string{"blake2": "blake2b", "blake2b512": "blake2b", "b2": "blake2b", "b3": "blake3"}
+
+// checksumHexLength maps the length of a hex digest to the algorithms producing it.
+// The lengths are shared: BLAKE3 digests look like SHA-256 ones and BLAKE2b-512
+// digests like SHA-512 ones, so a bare digest is checked against each of them.
+var checksumHexLength = map[int][]string{
+	32:  {"md5"},
+	40:  {"sha1"},
+	64:  {"sha256", "blake3"},
+	128: {"sha512", "blake2b"},
+}
+
+var (
+	// gnuChecksumLine matches sha256sum output: "<hex>  name", or "<hex> *name" in binary mode
+	gnuChecksumLine = regexp.MustCompile(`^\\?([0-9a-fA-F]+) [ *](.+)$`)
+	// bsdChecksumLine matches BSD and "--tag" output: "SHA256 (name) = <hex>"
+	bsdChecksumLine = regexp.MustCompile(`^\\?([A-Za-z0-9-]+) \((.+)\) ?= ([0-9a-fA-F]+)$`)
+)
+
+type checksumInput struct {
+	Path          string   `json:"path,omitempty" jsonschema:"File or directory to hash, '~' is expanded. Directories are hashed recursively. With sums_file, only the listed files below this path are verified"`
+	Algorithms    []string `json:"algorithms,omitempty" jsonschema:"Hash algorithms: md5, sha1, sha256, sha512, blake2b (BLAKE2b-512) or blake3. Defaults to sha256, or to the algorithm of the expected hash"`
+	Expected      string   `json:"expected,omitempty" jsonschema:"Expected hash of the file in hex, optionally prefixed with the algorithm (e.g. 'sha256:ab12...'). Without a prefix it is checked against every algorithm with that digest length, e.g. sha256 and blake3"`
+	SumsFile      string   `json:"sums_file,omitempty" jsonschema:"Checksum file to verify, such as SHA256SUMS, B2SUMS or installer.sha512, in sha256sum, b2sum, b3sum or BSD tag format. Listed paths are relative to its directory"`
+	IgnoreMissing bool     `json:"ignore_missing,omitempty" jsonschema:"Skip files listed in sums_file that do not exist instead of reporting them"`
+}
+
+type checksumEntry struct {
+	Path      string            `json:"path" jsonschema:"Full path of the file"`
+	Name      string            `json:"name" jsonschema:"Path relative to the hashed directory or checksum file"`
+	Size      int64             `json:"size" jsonschema:"Size in bytes"`
+	Hashes    map[string]string `json:"hashes,omitempty" jsonschema:"Hex digest by algorithm"`
+	Expected  string            `json:"expected,omitempty" jsonschema:"Expected hex digest"`
+	Algorithm string            `json:"algorithm,omitempty" jsonschema:"Algorithm of the expected digest, or the candidates when its length fits several and none matched"`
+	Status    string            `json:"status,omitempty" jsonschema:"Verification result: ok, mismatch, missing or error"`
+	Error     string            `json:"error,omitempty" jsonschema:"Why the file could not be hashed"`
+	// candidates are the algorithms the expected digest may have been made with
+	candidates []string
+}
+
+type checksumVerification struct {
+	AllMatch       bool     `json:"all_match" jsonschema:"Whether every verified file matched its expected hash"`
+	Matched        int      `json:"matched" jsonschema:"Files whose hash matched"`
+	Mismatched     int      `json:"mismatched" jsonschema:"Files whose hash differs from the expected one"`
+	Missing        int      `json:"missing" jsonschema:"Listed files that do not exist"`
+	Failed         int      `json:"failed" jsonschema:"Files that could not be read"`
+	Problems       []string `json:"problems" jsonschema:"One line per file that did not match, with the actual and expected hash"`
+	MalformedLines []string `json:"malformed_lines,omitempty" jsonschema:"Lines of the checksum file that could not be parsed"`
+}
+
+type checksumOutput struct {
+	Algorithms   []string              `json:"algorithms" jsonschema:"Algorithms that were computed"`
+	TotalFiles   int                   `json:"total_files" jsonschema:"Number of files hashed"`
+	TotalSize    int64                 `json:"total_size" jsonschema:"Bytes read"`
+	Files        []checksumEntry       `json:"files" jsonschema:"Files and their hashes"`
+	Sums         string                `json:"sums,omitempty" jsonschema:"Hashes of the first algorithm in sha256sum format, ready to save as a checksum file"`
+	Verification *checksumVerification `json:"verification,omitempty" jsonschema:"Result of comparing against the expected hash or checksum file"`
+}
+
+// checksumAlgorithm returns the canonical name of an algorithm, e.g. SHA-256 becomes sha256
+func checksumAlgorithm(name string) (string, error) {
+	alg := strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+	if canonical, ok := checksumAlgorithmAliases[alg]; ok {
+		alg = canonical
+	}
+	if _, ok := checksumAlgorithms[alg]; ok {
+		return alg, nil
+	}
+	return "", fmt.Errorf("unknown hash algorithm %q, use md5, sha1, sha256, sha512, blake2b or blake3", name)
+}
+
+// parseExpectedHash splits "sha256:ab12..." into the candidate algorithms and a lower
+// case digest, using every algorithm of the digest's length when there is no prefix
+func parseExpectedHash(expected string) ([]string, string, error) {
+	expected = strings.TrimSpace(expected)
+	alg := ""
+	if i := strings.IndexAny(expected, ":="); i > 0 {
+		var err error
+		if alg, err = checksumAlgorithm(expected[:i]); err != nil {
+			return nil, "", err
+		}
+		expected = strings.TrimSpace(expected[i+1:])
+	}
+
+	digest := strings.ToLower(expected)
+	if _, err := hex.DecodeString(digest); err != nil || digest == "" {
+		return nil, "", fmt.Errorf("expected hash %q is not a hex digest", expected)
+	}
+
+	detected, ok := checksumHexLength[len(digest)]
+	switch {
+	case alg == "" && !ok:
+		return nil, "", fmt.Errorf("cannot tell the algorithm of a %d character hash, prefix it like 'sha256:'", len(digest))
+	case alg == "":
+		return detected, digest, nil
+	case !slices.Contains(detected, alg):
+		return nil, "", fmt.Errorf("expected hash has %d characters, a %s hash has %d", len(digest), alg, hex.EncodedLen(checksumAlgorithms[alg]().Size()))
+	}
+	return []string{alg}, digest, nil
+}
+
+type checksumListEntry struct {
+	name       string
+	algorithms []string
+	digest     string
+}
+
+// unescapeChecksumName undoes the escaping sha256sum applies to names containing
+// a backslash or a newline, which it marks with a leading backslash
+func unescapeChecksumName(line, name string) string {
+	if !strings.HasPrefix(line, `\`) {
+		return name
+	}
+	return strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\r`, "\r").Replace(name)
+}
+
+// readChecksumFile parses a checksum file in sha256sum or BSD tag format
+func readChecksumFile(path string) ([]checksumListEntry, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var entries []checksumListEntry
+	var malformed []string
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var entry checksumListEntry
+		if m := bsdChecksumLine.FindStringSubmatch(line); m != nil {
+			alg, err := checksumAlgorithm(m[1])
+			if err != nil {
+				malformed = append(malformed, fmt.Sprintf("line %d: %v", n, err))
+				continue
+			}
+			entry = checksumListEntry{name: unescapeChecksumName(line, m[2]), algorithms: []string{alg}, digest: strings.ToLower(m[3])}
+		} else if m := gnuChecksumLine.FindStringSubmatch(line); m != nil {
+			// b2sum and b3sum lines look like sha512sum and sha256sum ones
+			entry = checksumListEntry{name: unescapeChecksumName(line, m[2]), digest: strings.ToLower(m[1])}
+			entry.algorithms = checksumHexLength[len(entry.digest)]
+		} else {
+			malformed = append(malformed, fmt.Sprintf("line %d: %s", n, line))
+			continue
+		}
+
+		if len(entry.algorithms) == 0 || len(entry.digest) != hex.EncodedLen(checksumAlgorithms[entry.algorithms[0]]().Size()) {
+			malformed = append(malformed, fmt.Sprintf("line %d: unexpected hash length for %s", n, entry.name))
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return entries, malformed, nil
+}
+
+// progressWriter reports the bytes written through it as progress
+type progressWriter struct {
+	progress *progressReporter
+	message  string
+}
+
+func (w progressWriter) Write(p []byte) (int, error) {
+	w.progress.add(float64(len(p)), w.message)
+	return len(p), nil
+}
+
+// hashFile streams a file once through every algorithm
+func hashFile(ctx context.Context, path string, algorithms []string, progress *progressReporter) (map[string]string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	hashers := make([]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, 0, len(algorithms)+1)
+	for i, alg := range algorithms {
+		hashers[i] = checksumAlgorithms[alg]()
+		writers = append(writers, hashers[i])
+	}
+	writers = append(writers, progressWriter{progress, "Hashing " + filepath.Base(path)})
+
+	size, err := io.Copy(io.MultiWriter(writers...), ctxReader{ctx, f})
+	if err != nil {
+		return nil, size, err
+	}
+
+	hashes := make(map[string]string, len(algorithms))
+	for i, alg := range algorithms {
+		hashes[alg] = hex.EncodeToString(hashers[i].Sum(nil))
+	}
+	return hashes, size, nil
+}
+
+// isWithin reports whether path is root or below it
+func isWithin(path, root string) bool {
+	return path == root || strings.HasPrefix(path, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator))
+}
+
+// Checksum computes MD5, SHA-1, SHA-2, BLAKE2b or BLAKE3 hashes of files and verifies
+// them against an expected hash or a checksum file
+func Checksum(ctx context.Context, req *mcp.CallToolRequest, input checksumInput) (*mcp.CallToolResult, *checksumOutput, error) {
+	if input.Path == "" && input.SumsFile == "" {
+		return nil, nil, fmt.Errorf("either path or sums_file is required")
+	}
+	if input.Expected != "" && input.SumsFile != "" {
+		return nil, nil, fmt.Errorf("expected and sums_file cannot be used together")
+	}
+
+	output := &checksumOutput{Algorithms: []string{}, Files: []checksumEntry{}}
+
+	requested := make(map[string]bool)
+	for _, name := range input.Algorithms {
+		alg, err := checksumAlgorithm(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		requested[alg] = true
+	}
+
+	var expectedAlgs []string
+	var expectedDigest string
+	if input.Expected != "" {
+		var err error
+		if expectedAlgs, expectedDigest, err = parseExpectedHash(input.Expected); err != nil {
+			return nil, nil, err
+		}
+		// Other algorithms with the same digest length are only tried on a mismatch
+		requested[expectedAlgs[0]] = true
+	}
+	if len(requested) == 0 && input.SumsFile == "" {
+		requested["sha256"] = true
+	}
+	for _, alg := range checksumAlgorithmOrder {
+		if requested[alg] {
+			output.Algorithms = append(output.Algorithms, alg)
+		}
+	}
+
+	root := ""
+	if input.Path != "" {
+		path, err := expandHome(input.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if root, err = filepath.Abs(path); err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve %s: %w", input.Path, err)
+		}
+	}
+
+	// Work out which files to hash and what they are expected to hash to
+	var files []checksumEntry
+	verification := &checksumVerification{Problems: []string{}}
+	if input.SumsFile != "" {
+		sumsFile, err := expandHome(input.SumsFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		if sumsFile, err = filepath.Abs(sumsFile); err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve %s: %w", input.SumsFile, err)
+		}
+
+		entries, malformed, err := readChecksumFile(sumsFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		verification.MalformedLines = malformed
+		if len(entries) == 0 {
+			return nil, nil, fmt.Errorf("no checksums found in %s", sumsFile)
+		}
+
+		dir := filepath.Dir(sumsFile)
+		for _, e := range entries {
+			path := filepath.FromSlash(e.name)
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			if root != "" && !isWithin(path, root) {
+				continue
+			}
+			files = append(files, checksumEntry{Path: path, Name: e.name, Expected: e.digest, candidates: e.algorithms})
+			if alg := e.algorithms[0]; !requested[alg] {
+				requested[alg] = true
+				output.Algorithms = append(output.Algorithms, alg)
+			}
+		}
+		if len(files) == 0 {
+			return nil, nil, fmt.Errorf("%s lists no files below %s", sumsFile, root)
+		}
+	} else {
+		info, err := os.Stat(root)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s: %w", root, err)
+		}
+		if expectedDigest != "" && info.IsDir() {
+			return nil, nil, fmt.Errorf("an expected hash can only be checked against a single file, %s is a directory", root)
+		}
+
+		// A file, or a symlink to one, is hashed as is
+		paths := []string{root}
+		if info.IsDir() {
+			if paths, err = collectArchivePaths(ctx, []string{root}); err != nil {
+				return nil, nil, err
+			}
+		}
+		for _, path := range paths {
+			name := filepath.Base(path)
+			if info.IsDir() {
+				name, _ = filepath.Rel(root, path)
+				name = filepath.ToSlash(name)
+			}
+			files = append(files, checksumEntry{Path: path, Name: name, Expected: expectedDigest, candidates: expectedAlgs})
+		}
+	}
+
+	// Size the files up front so progress can be reported in bytes
+	progress := newProgressReporter(ctx, req)
+	var total int64
+	for i := range files {
+		info, err := os.Stat(files[i].Path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				files[i].Status = "missing"
+			} else {
+				files[i].Status = "error"
+				files[i].Error = err.Error()
+			}
+			continue
+		}
+		files[i].Size = info.Size()
+		total += info.Size()
+	}
+	progress.setTotal(float64(total), fmt.Sprintf("Hashing %d files", len(files)))
+
+	for _, file := range files {
+		if file.Status == "missing" && input.IgnoreMissing {
+			continue
+		}
+
+		if file.Status == "" {
+			// Checksum files can mix algorithms, only compute the one each entry needs
+			algorithms := output.Algorithms
+			if input.SumsFile != "" && len(input.Algorithms) == 0 {
+				algorithms = file.candidates[:1]
+			}
+
+			hashes, size, err := hashFile(ctx, file.Path, algorithms, progress)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, nil, ctx.Err()
+				}
+				file.Status = "error"
+				file.Error = err.Error()
+			} else {
+				file.Hashes = hashes
+				file.Size = size
+				output.TotalFiles++
+				output.TotalSize += size
+			}
+		}
+
+		if file.Expected != "" {
+			switch file.Status {
+			case "missing":
+				verification.Missing++
+				verification.Problems = append(verification.Problems, fmt.Sprintf("%s: file not found", file.Name))
+			case "error":
+				verification.Failed++
+				verification.Problems = append(verification.Problems, fmt.Sprintf("%s: %s", file.Name, file.Error))
+			default:
+				// The digest length fits several algorithms, hash again with the others
+				if file.Hashes[file.candidates[0]] != file.Expected && len(file.candidates) > 1 {
+					var others []string
+					for _, alg := range file.candidates[1:] {
+						if _, ok := file.Hashes[alg]; !ok {
+							others = append(others, alg)
+						}
+					}
+					if len(others) > 0 {
+						total += file.Size
+						progress.setTotal(float64(total), fmt.Sprintf("Hashing %s with %s", file.Name, strings.Join(others, ", ")))
+						hashes, _, err := hashFile(ctx, file.Path, others, progress)
+						if err != nil {
+							if ctx.Err() != nil {
+								return nil, nil, ctx.Err()
+							}
+							file.Status = "error"
+							file.Error = err.Error()
+							verification.Failed++
+							verification.Problems = append(verification.Problems, fmt.Sprintf("%s: %s", file.Name, file.Error))
+							break
+						}
+						for alg, digest := range hashes {
+							file.Hashes[alg] = digest
+						}
+						for _, alg := range others {
+							if !slices.Contains(output.Algorithms, alg) {
+								output.Algorithms = append(output.Algorithms, alg)
+							}
+						}
+					}
+				}
+
+				var actual []string
+				for _, alg := range file.candidates {
+					if file.Hashes[alg] == file.Expected {
+						file.Algorithm = alg
+						break
+					}
+					actual = append(actual, fmt.Sprintf("%s is %s", alg, file.Hashes[alg]))
+				}
+				if file.Algorithm != "" {
+					file.Status = "ok"
+					verification.Matched++
+				} else {
+					file.Status = "mismatch"
+					file.Algorithm = strings.Join(file.candidates, " or ")
+					verification.Mismatched++
+					verification.Problems = append(verification.Problems, fmt.Sprintf("%s: %s, expected %s", file.Name, strings.Join(actual, ", "), file.Expected))
+				}
+			}
+		} else if file.Status == "error" {
+			verification.Failed++
+		}
+
+		output.Files = append(output.Files, file)
+	}
+
+	if input.SumsFile != "" || expectedDigest != "" {
+		verification.AllMatch = verification.Matched > 0 && verification.Mismatched == 0 && verification.Missing == 0 && verification.Failed == 0
+		output.Verification = verification
+	} else if len(output.Algorithms) > 0 {
+		var sums strings.Builder
+		for _, file := range output.Files {
+			if digest, ok := file.Hashes[output.Algorithms[0]]; ok {
+				fmt.Fprintf(&sums, "%s  %s\n", digest, file.Name)
+			}
+		}
+		output.Sums = sums.String()
+	}
+
+	return nil, output, nil
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "abc")
+	if err := os.WriteFile(path, []byte("abc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"md5":     "900150983cd24fb0d6963f7d28e17f72",
+		"sha1":    "a9993e364706816aba3e25717850c26c9cd0d89d",
+		"sha256":  "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		"blake2b": "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		"blake3":  "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85",
+	}
+
+	var algorithms []string
+	for alg := range want {
+		algorithms = append(algorithms, alg)
+	}
+	got, size, err := hashFile(context.Background(), path, algorithms, newProgressReporter(context.Background(), nil))
+	if err != nil {
+		t.Fatalf("hashFile: %v", err)
+	}
+	if size != 3 {
+		t.Errorf("size = %d, want 3", size)
+	}
+	for alg, digest := range want {
+		if got[alg] != digest {
+			t.Errorf("%s = %s, want %s", alg, got[alg], digest)
+		}
+	}
+}
+
+func TestParseExpectedHash(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "900150983cd24fb0d6963f7d28e17f72", want: "md5"},
+		{in: "sha256:BA7816BF8F01CFEA414140DE5DAE2223B00361A396177A9CB410FF61F20015AD", want: "sha256"},
+		{in: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", want: "sha256 blake3"},
+		{in: strings.Repeat("a", 128), want: "sha512 blake2b"},
+		{in: "BLAKE2b-512=" + strings.Repeat("a", 128), want: "blake2b"},
+		{in: "b3:" + strings.Repeat("a", 64), want: "blake3"},
+		{in: "sha1:" + strings.Repeat("a", 64), wantErr: true},
+		{in: strings.Repeat("a", 50), wantErr: true},
+		{in: "xyz:" + strings.Repeat("a", 64), wantErr: true},
+		{in: "not hex", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		algs, _, err := parseExpectedHash(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseExpectedHash(%q) = %v, want an error", tt.in, algs)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseExpectedHash(%q): %v", tt.in, err)
+			continue
+		}
+		if got := strings.Join(algs, " "); got != tt.want {
+			t.Errorf("parseExpectedHash(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReadChecksumFile(t *testing.T) {
+	content := strings.Join([]string{
+		"# comment",
+		"ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad  abc.txt",
+		"900150983cd24fb0d6963f7d28e17f72 *bin/abc.bin",
+		`\ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad  a\\b`,
+		"BLAKE2b (abc.txt) = ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		"SHA1 (x) = 1234",
+		"garbage",
+		"",
+	}, "\r\n")
+	path := filepath.Join(t.TempDir(), "SUMS")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, malformed, err := readChecksumFile(path)
+	if err != nil {
+		t.Fatalf("readChecksumFile: %v", err)
+	}
+
+	want := []string{"abc.txt sha256,blake3", "bin/abc.bin md5", `a\b sha256,blake3`, "abc.txt blake2b"}
+	if len(entries) != len(want) {
+		t.Fatalf("entries = %+v, want %v", entries, want)
+	}
+	for i, e := range entries {
+		if got := e.name + " " + strings.Join(e.algorithms, ","); got != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if len(malformed) != 2 {
+		t.Errorf("malformed = %q, want the SHA1 and garbage lines", malformed)
+	}
+}
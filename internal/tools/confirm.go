@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// confirmAction asks the user to approve a destructive action through MCP
+// elicitation. It returns false when the user declines or dismisses the prompt.
+func confirmAction(ctx context.Context, req *mcp.CallToolRequest, message string) (bool, error) {
+	if req == nil || req.Session == nil {
+		return false, fmt.Errorf("confirmation requires an MCP client session")
+	}
+
+	res, err := req.Session.Elicit(ctx, &mcp.ElicitParams{
+		Message: message,
+		RequestedSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"confirm": map[string]any{
+					"type":        "boolean",
+					"description": "Set to true to proceed",
+				},
+			},
+			"required": []string{"confirm"},
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to ask for confirmation (the client may not support elicitation): %w", err)
+	}
+
+	if res.Action != "accept" {
+		return false, nil
+	}
+
+	confirmed, _ := res.Content["confirm"].(bool)
+
+	return confirmed, nil
+}
@@ -0,0 +1,767 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultCronCount = 5
+	maxCronCount     = 100
+	// cronSearchDays bounds the search for fire times; 28 years covers every
+	// combination of weekday, leap year and month
+	cronSearchDays = 28 * 366
+)
+
+// cronMacros maps @-macros to their standard 5-field expressions
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	cronMonthNames = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+	cronDayNames   = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+	ordinals       = []string{"", "first", "second", "third", "fourth", "fifth"}
+)
+
+type cronScheduleInput struct {
+	Expression string `json:"expression" jsonschema:"Cron expression: 5 fields (minute hour day-of-month month day-of-week), 6 fields with leading seconds, Quartz style (seconds ... day-of-week [year] with ?, L, W and #) or a macro such as '@daily'"`
+	Dialect    string `json:"dialect,omitempty" jsonschema:"'standard', 'seconds' or 'quartz'. Detected from the number of fields and special characters when omitted"`
+	Timezone   string `json:"timezone,omitempty" jsonschema:"IANA time zone the schedule runs in, defaults to the server's local zone"`
+	From       string `json:"from,omitempty" jsonschema:"Time to compute fire times from, in any format accepted by time_convert. Defaults to now"`
+	Next       int    `json:"next,omitempty" jsonschema:"Number of upcoming fire times to list, defaults to 5 (max 100)"`
+	Previous   int    `json:"previous,omitempty" jsonschema:"Number of previous fire times to list (max 100)"`
+}
+
+type cronFire struct {
+	Time string `json:"time" jsonschema:"Fire time in RFC3339 format in the schedule's time zone"`
+	Unix int64  `json:"unix" jsonschema:"Unix time in seconds"`
+	Note string `json:"note,omitempty" jsonschema:"How a DST transition affected this fire time"`
+}
+
+type cronScheduleOutput struct {
+	Expression  string            `json:"expression" jsonschema:"Expression that was parsed, with macros expanded"`
+	Dialect     string            `json:"dialect" jsonschema:"Dialect the expression was parsed as"`
+	Fields      map[string]string `json:"fields" jsonschema:"Expression of each field"`
+	Description string            `json:"description" jsonschema:"Plain English explanation of the schedule"`
+	Timezone    string            `json:"timezone" jsonschema:"Time zone the fire times are computed in"`
+	Next        []cronFire        `json:"next" jsonschema:"Upcoming fire times"`
+	Previous    []cronFire        `json:"previous" jsonschema:"Previous fire times, most recent first"`
+	Notes       []string          `json:"notes,omitempty" jsonschema:"Notes about how the expression was interpreted"`
+}
+
+// cronFieldSpec describes the allowed values of a cron field
+type cronFieldSpec struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+// cronSpec is a parsed cron expression
+type cronSpec struct {
+	seconds, minutes, hours, dom, months, dow map[int]bool
+	years                                     map[int]bool // nil means every year
+
+	domAny, dowAny bool // field is '*' or '?'
+	quartz         bool
+
+	lastDOM        bool // L, or L-n with lastDOMOffset
+	lastDOMOffset  int
+	lastWeekday    bool  // LW
+	nearestWeekday []int // nW
+	lastDOW        []int // nL
+	nthDOW         [][2]int
+
+	exprs []string // source expression of each field, in order
+}
+
+// parseCronValue parses a number or a name of the field
+func parseCronValue(s string, spec cronFieldSpec, quartzDOW bool) (int, error) {
+	if spec.names != nil {
+		for i, name := range spec.names {
+			if strings.EqualFold(s, name) {
+				if spec.name == "month" {
+					return i + 1, nil
+				}
+				return i, nil
+			}
+		}
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%s field: invalid value '%s'", spec.name, s)
+	}
+
+	if quartzDOW {
+		if n < 1 || n > 7 {
+			return 0, fmt.Errorf("%s field: value %d out of range 1-7 (Quartz uses 1 for Sunday)", spec.name, n)
+		}
+		return n - 1, nil
+	}
+
+	if n < spec.min || n > spec.max {
+		return 0, fmt.Errorf("%s field: value %d out of range %d-%d", spec.name, n, spec.min, spec.max)
+	}
+
+	return n, nil
+}
+
+// parseCronField parses a comma separated list of values, ranges and steps
+func parseCronField(expr string, spec cronFieldSpec, quartzDOW bool, c *cronSpec) (map[int]bool, error) {
+	values := make(map[int]bool)
+	hi := spec.max
+	if spec.name == "day of week" {
+		hi = 6
+	}
+
+	for _, part := range strings.Split(expr, ",") {
+		if part == "" {
+			return nil, fmt.Errorf("%s field: empty list element in '%s'", spec.name, expr)
+		}
+		upper := strings.ToUpper(part)
+
+		// Special day-of-month and day-of-week forms
+		switch {
+		case spec.name == "day of month" && (upper == "L" || strings.HasPrefix(upper, "L-")):
+			c.lastDOM = true
+			if upper != "L" {
+				n, err := strconv.Atoi(upper[2:])
+				if err != nil || n < 0 || n > 30 {
+					return nil, fmt.Errorf("day of month field: invalid offset in '%s', expected L-1 to L-30", part)
+				}
+				c.lastDOMOffset = n
+			}
+			continue
+		case spec.name == "day of month" && upper == "LW":
+			c.lastWeekday = true
+			continue
+		case spec.name == "day of month" && strings.HasSuffix(upper, "W"):
+			n, err := parseCronValue(upper[:len(upper)-1], spec, false)
+			if err != nil {
+				return nil, err
+			}
+			c.nearestWeekday = append(c.nearestWeekday, n)
+			continue
+		case spec.name == "day of week" && strings.HasSuffix(upper, "L") && len(upper) > 1:
+			n, err := parseCronValue(upper[:len(upper)-1], spec, quartzDOW)
+			if err != nil {
+				return nil, err
+			}
+			c.lastDOW = append(c.lastDOW, n%7)
+			continue
+		case spec.name == "day of week" && strings.Contains(upper, "#"):
+			day, nth, _ := strings.Cut(upper, "#")
+			n, err := parseCronValue(day, spec, quartzDOW)
+			if err != nil {
+				return nil, err
+			}
+			k, err := strconv.Atoi(nth)
+			if err != nil || k < 1 || k > 5 {
+				return nil, fmt.Errorf("day of week field: invalid occurrence in '%s', expected #1 to #5", part)
+			}
+			c.nthDOW = append(c.nthDOW, [2]int{n % 7, k})
+			continue
+		}
+
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("%s field: invalid step '%s' in '%s', expected a positive number", spec.name, stepPart, part)
+			}
+		}
+
+		var start, end int
+		switch {
+		case rangePart == "*" || rangePart == "?":
+			if rangePart == "?" && spec.name != "day of month" && spec.name != "day of week" {
+				return nil, fmt.Errorf("%s field: '?' is only allowed in the day-of-month and day-of-week fields", spec.name)
+			}
+			start, end = spec.min, hi
+			if quartzDOW {
+				start = 0
+			}
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseCronValue(a, spec, quartzDOW); err != nil {
+				return nil, err
+			}
+			if end, err = parseCronValue(b, spec, quartzDOW); err != nil {
+				return nil, err
+			}
+			if start > end {
+				return nil, fmt.Errorf("%s field: range start %s is after range end %s in '%s'", spec.name, a, b, part)
+			}
+		default:
+			var err error
+			if start, err = parseCronValue(rangePart, spec, quartzDOW); err != nil {
+				return nil, err
+			}
+			end = start
+			if hasStep {
+				end = hi
+			}
+		}
+
+		for v := start; v <= end; v += step {
+			if spec.name == "day of week" {
+				values[v%7] = true // 7 is Sunday too
+			} else {
+				values[v] = true
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// isQuartzSyntax reports whether the i-th field (seconds first) uses ?, L, W or #
+func isQuartzSyntax(i int, field string) bool {
+	upper := strings.ToUpper(field)
+	switch i {
+	case 3:
+		return strings.ContainsAny(upper, "?LW")
+	case 5:
+		return strings.ContainsAny(upper, "?#") || (len(upper) > 1 && strings.HasSuffix(upper, "L") && upper[0] >= '0' && upper[0] <= '9')
+	}
+	return false
+}
+
+// parseCron parses an expression in the given dialect ("" to detect it)
+func parseCron(expr, dialect string) (*cronSpec, string, []string, error) {
+	var notes []string
+
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@") {
+		expanded, ok := cronMacros[strings.ToLower(expr)]
+		if !ok {
+			if strings.EqualFold(expr, "@reboot") {
+				return nil, "", nil, fmt.Errorf("@reboot runs once at startup and has no schedule")
+			}
+			return nil, "", nil, fmt.Errorf("unknown macro '%s', expected one of @yearly, @annually, @monthly, @weekly, @daily, @midnight or @hourly", expr)
+		}
+		notes = append(notes, fmt.Sprintf("%s expands to '%s'", expr, expanded))
+		expr = expanded
+	}
+
+	fields := strings.Fields(expr)
+
+	dialect = strings.ToLower(dialect)
+	if dialect == "" {
+		switch len(fields) {
+		case 5:
+			dialect = "standard"
+		case 6:
+			dialect = "seconds"
+			if isQuartzSyntax(3, fields[3]) || isQuartzSyntax(5, fields[5]) {
+				dialect = "quartz"
+			} else {
+				notes = append(notes, "6 fields were read as seconds followed by a standard expression (day of week 0-7 with 0 and 7 meaning Sunday); use dialect 'quartz' for Quartz numbering (1 = Sunday)")
+			}
+		case 7:
+			dialect = "quartz"
+		default:
+			return nil, "", nil, fmt.Errorf("expected 5, 6 or 7 fields, got %d in '%s'", len(fields), expr)
+		}
+	}
+
+	switch dialect {
+	case "standard":
+		if len(fields) != 5 {
+			return nil, "", nil, fmt.Errorf("standard cron expressions have 5 fields, got %d", len(fields))
+		}
+		fields = append([]string{"0"}, fields...)
+	case "seconds":
+		if len(fields) != 6 {
+			return nil, "", nil, fmt.Errorf("cron expressions with seconds have 6 fields, got %d", len(fields))
+		}
+	case "quartz":
+		if len(fields) != 6 && len(fields) != 7 {
+			return nil, "", nil, fmt.Errorf("Quartz expressions have 6 or 7 fields, got %d", len(fields))
+		}
+	default:
+		return nil, "", nil, fmt.Errorf("unknown dialect '%s', expected 'standard', 'seconds' or 'quartz'", dialect)
+	}
+
+	c := &cronSpec{quartz: dialect == "quartz", exprs: fields}
+
+	specs := []cronFieldSpec{
+		{name: "second", min: 0, max: 59},
+		{name: "minute", min: 0, max: 59},
+		{name: "hour", min: 0, max: 23},
+		{name: "day of month", min: 1, max: 31},
+		{name: "month", min: 1, max: 12, names: cronMonthNames},
+		{name: "day of week", min: 0, max: 7, names: cronDayNames},
+		{name: "year", min: 1970, max: 2099},
+	}
+
+	targets := []*map[int]bool{&c.seconds, &c.minutes, &c.hours, &c.dom, &c.months, &c.dow, &c.years}
+
+	for i, field := range fields {
+		if !c.quartz && isQuartzSyntax(i, field) {
+			return nil, "", nil, fmt.Errorf("%s field: '%s' uses Quartz syntax, which is only valid in the Quartz dialect", specs[i].name, field)
+		}
+
+		values, err := parseCronField(field, specs[i], c.quartz && i == 5, c)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		*targets[i] = values
+	}
+
+	if len(fields) == 6 {
+		c.years = nil
+	} else if fields[6] == "*" {
+		c.years = nil
+	}
+
+	c.domAny = fields[3] == "?" || strings.HasPrefix(fields[3], "*")
+	c.dowAny = fields[5] == "?" || strings.HasPrefix(fields[5], "*")
+
+	if c.quartz && fields[3] != "?" && fields[5] != "?" {
+		return nil, "", nil, fmt.Errorf("Quartz expressions must use '?' in either the day-of-month or the day-of-week field")
+	}
+	if c.quartz && fields[3] == "?" && fields[5] == "?" {
+		return nil, "", nil, fmt.Errorf("Quartz expressions cannot use '?' in both the day-of-month and the day-of-week field")
+	}
+
+	return c, dialect, notes, nil
+}
+
+// lastDayOfMonth returns the number of days in the month
+func lastDayOfMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// nearestWeekdayTo returns the weekday closest to day in the same month
+func nearestWeekdayTo(year int, month time.Month, day int) int {
+	last := lastDayOfMonth(year, month)
+	if day > last {
+		day = last
+	}
+
+	switch time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday() {
+	case time.Saturday:
+		if day == 1 {
+			return 3
+		}
+		return day - 1
+	case time.Sunday:
+		if day == last {
+			return day - 2
+		}
+		return day + 1
+	}
+
+	return day
+}
+
+// matchesDay reports whether the schedule fires on the date
+func (c *cronSpec) matchesDay(year int, month time.Month, day int) bool {
+	if c.years != nil && !c.years[year] {
+		return false
+	}
+	if !c.months[int(month)] {
+		return false
+	}
+
+	last := lastDayOfMonth(year, month)
+	weekday := int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Weekday())
+
+	domMatch := c.dom[day] ||
+		(c.lastDOM && day == last-c.lastDOMOffset) ||
+		(c.lastWeekday && day == nearestWeekdayTo(year, month, last))
+	for _, n := range c.nearestWeekday {
+		if day == nearestWeekdayTo(year, month, n) {
+			domMatch = true
+		}
+	}
+
+	dowMatch := c.dow[weekday]
+	for _, n := range c.lastDOW {
+		if weekday == n && day+7 > last {
+			dowMatch = true
+		}
+	}
+	for _, nk := range c.nthDOW {
+		if weekday == nk[0] && (day-1)/7+1 == nk[1] {
+			dowMatch = true
+		}
+	}
+
+	switch {
+	case c.quartz && c.exprs[3] == "?":
+		return dowMatch
+	case c.quartz:
+		return domMatch
+	case c.domAny || c.dowAny:
+		// A '*' field matches every day, so this is effectively the other field
+		return domMatch && dowMatch
+	default:
+		// Vixie cron fires when either restricted field matches
+		return domMatch || dowMatch
+	}
+}
+
+// sortedKeys returns the keys of a set in ascending order
+func sortedKeys(set map[int]bool) []int {
+	keys := make([]int, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// fireTimesOnDay returns the instants the schedule fires on a calendar day in
+// loc, in ascending order. Wall times skipped by DST fire at the end of the
+// gap; wall times repeated by DST fire once, or twice for hour wildcards.
+func (c *cronSpec) fireTimesOnDay(year int, month time.Month, day int, loc *time.Location) []cronFire {
+	var fires []cronFire
+	seen := make(map[int64]bool)
+
+	hourWildcard := c.exprs[2] == "*" || strings.HasPrefix(c.exprs[2], "*/")
+
+	for _, h := range sortedKeys(c.hours) {
+		for _, m := range sortedKeys(c.minutes) {
+			for _, s := range sortedKeys(c.seconds) {
+				wall := time.Date(year, month, day, h, m, s, 0, time.UTC)
+				t, dst := resolveLocalTime(wall, loc)
+
+				instants := []time.Time{t}
+				note := ""
+
+				if dst != nil {
+					switch dst.Status {
+					case "nonexistent":
+						// Fire at the moment the clocks jump, like Vixie cron
+						start, _ := t.ZoneBounds()
+						instants = []time.Time{start}
+						note = fmt.Sprintf("%s does not exist because of the DST change and fires when the clocks jump forward", wall.Format("15:04:05"))
+					case "ambiguous":
+						if hourWildcard {
+							second, _ := time.Parse(time.RFC3339, dst.Candidates[1])
+							instants = append(instants, second)
+							note = fmt.Sprintf("%s occurs twice because of the DST change and fires both times", wall.Format("15:04:05"))
+						} else {
+							note = fmt.Sprintf("%s occurs twice because of the DST change and fires only the first time", wall.Format("15:04:05"))
+						}
+					}
+				}
+
+				for _, instant := range instants {
+					if seen[instant.Unix()] {
+						continue
+					}
+					seen[instant.Unix()] = true
+					fires = append(fires, cronFire{Time: instant.In(loc).Format(time.RFC3339), Unix: instant.Unix(), Note: note})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fires, func(i, j int) bool { return fires[i].Unix < fires[j].Unix })
+
+	return fires
+}
+
+// fireTimes lists up to count fire times after (or before, if backward) from
+func (c *cronSpec) fireTimes(from time.Time, loc *time.Location, count int, backward bool) []cronFire {
+	fires := []cronFire{}
+	if count <= 0 {
+		return fires
+	}
+
+	local := from.In(loc)
+	date := time.Date(local.Year(), local.Month(), local.Day(), 12, 0, 0, 0, time.UTC)
+	step := 1
+	if backward {
+		step = -1
+	}
+
+	for i := 0; i < cronSearchDays && len(fires) < count; i++ {
+		d := date.AddDate(0, 0, i*step)
+		if !c.matchesDay(d.Year(), d.Month(), d.Day()) {
+			continue
+		}
+
+		day := c.fireTimesOnDay(d.Year(), d.Month(), d.Day(), loc)
+		if backward {
+			for j := len(day) - 1; j >= 0 && len(fires) < count; j-- {
+				if day[j].Unix < from.Unix() {
+					fires = append(fires, day[j])
+				}
+			}
+			continue
+		}
+
+		for _, f := range day {
+			if len(fires) < count && f.Unix > from.Unix() {
+				fires = append(fires, f)
+			}
+		}
+	}
+
+	return fires
+}
+
+// describeCronField describes a field's values using unit names
+func describeCronField(expr, unit string, names []string, quartzDOW bool) string {
+	name := func(v string) string {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			for i, short := range names {
+				if strings.EqualFold(v, short) {
+					n = i
+					if unit == "month" {
+						n++
+					}
+				}
+			}
+		} else if quartzDOW {
+			n--
+		}
+		if unit == "month" {
+			return time.Month(n).String()
+		}
+		return time.Weekday(n % 7).String()
+	}
+	if names == nil {
+		name = func(v string) string { return v }
+	}
+
+	var parts []string
+	for _, part := range strings.Split(expr, ",") {
+		rangePart, step, hasStep := strings.Cut(part, "/")
+		switch {
+		case rangePart == "*" && hasStep:
+			parts = append(parts, fmt.Sprintf("every %s %ss", step, unit))
+		case rangePart == "*":
+			parts = append(parts, "every "+unit)
+		case strings.Contains(rangePart, "-") && hasStep:
+			a, b, _ := strings.Cut(rangePart, "-")
+			parts = append(parts, fmt.Sprintf("every %s %ss from %s through %s", step, unit, name(a), name(b)))
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			parts = append(parts, fmt.Sprintf("%s through %s", name(a), name(b)))
+		case hasStep:
+			parts = append(parts, fmt.Sprintf("every %s %ss starting at %s", step, unit, name(rangePart)))
+		default:
+			parts = append(parts, name(rangePart))
+		}
+	}
+
+	return joinEnglish(parts, "and")
+}
+
+// joinEnglish joins items as "a, b and c"
+func joinEnglish(items []string, conj string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	}
+	return strings.Join(items[:len(items)-1], ", ") + " " + conj + " " + items[len(items)-1]
+}
+
+// isPlainNumberList reports whether a field is a list of single values
+func isPlainNumberList(expr string) bool {
+	for _, part := range strings.Split(expr, ",") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// describe explains the schedule in plain English
+func (c *cronSpec) describe() string {
+	sec, min, hour := c.exprs[0], c.exprs[1], c.exprs[2]
+	var parts []string
+
+	if isPlainNumberList(min) && isPlainNumberList(hour) && isPlainNumberList(sec) && strings.Count(sec, ",") == 0 {
+		var times []string
+		for _, h := range sortedKeys(c.hours) {
+			for _, m := range sortedKeys(c.minutes) {
+				if sec == "0" {
+					times = append(times, fmt.Sprintf("%02d:%02d", h, m))
+				} else {
+					times = append(times, fmt.Sprintf("%02d:%02d:%s", h, m, fmt.Sprintf("%02s", sec)))
+				}
+			}
+		}
+		parts = append(parts, "at "+joinEnglish(times, "and"))
+	} else {
+		if sec != "0" {
+			if sec == "*" {
+				parts = append(parts, "every second")
+			} else if isPlainNumberList(sec) {
+				parts = append(parts, "at second "+describeCronField(sec, "second", nil, false))
+			} else {
+				parts = append(parts, describeCronField(sec, "second", nil, false))
+			}
+		}
+		switch {
+		case min == "*" && sec != "0":
+		case min == "*":
+			parts = append(parts, "every minute")
+		case isPlainNumberList(min):
+			parts = append(parts, "at minute "+describeCronField(min, "minute", nil, false))
+		default:
+			parts = append(parts, describeCronField(min, "minute", nil, false))
+		}
+		switch {
+		case hour == "*":
+			if min != "*" && isPlainNumberList(min) {
+				parts[len(parts)-1] += " of every hour"
+			}
+		case isPlainNumberList(hour):
+			parts = append(parts, "past hour "+describeCronField(hour, "hour", nil, false))
+		default:
+			parts = append(parts, "during hours "+describeCronField(hour, "hour", nil, false))
+		}
+	}
+
+	var days []string
+	if !c.domAny {
+		var d []string
+		if len(c.dom) > 0 {
+			d = append(d, "day "+describeCronField(strings.Join(filterCronSpecials(c.exprs[3]), ","), "day", nil, false))
+		}
+		if c.lastDOM {
+			if c.lastDOMOffset > 0 {
+				d = append(d, fmt.Sprintf("the day %d days before the last day", c.lastDOMOffset))
+			} else {
+				d = append(d, "the last day")
+			}
+		}
+		if c.lastWeekday {
+			d = append(d, "the last weekday")
+		}
+		for _, n := range c.nearestWeekday {
+			d = append(d, fmt.Sprintf("the weekday nearest day %d", n))
+		}
+		days = append(days, "on "+joinEnglish(d, "and")+" of the month")
+	}
+	if !c.dowAny {
+		var d []string
+		if len(c.dow) > 0 {
+			d = append(d, describeCronField(strings.Join(filterCronSpecials(c.exprs[5]), ","), "day", cronDayNames, c.quartz))
+		}
+		for _, n := range c.lastDOW {
+			d = append(d, fmt.Sprintf("the last %s of the month", time.Weekday(n)))
+		}
+		for _, nk := range c.nthDOW {
+			d = append(d, fmt.Sprintf("the %s %s of the month", ordinals[nk[1]], time.Weekday(nk[0])))
+		}
+		days = append(days, "on "+joinEnglish(d, "and"))
+	}
+	if len(days) > 0 {
+		conj := "and"
+		if !c.quartz && !c.domAny && !c.dowAny {
+			conj = "or"
+		}
+		parts = append(parts, strings.Join(days, " "+conj+" "))
+	}
+
+	if c.exprs[4] != "*" {
+		parts = append(parts, "in "+describeCronField(c.exprs[4], "month", cronMonthNames, false))
+	}
+	if len(c.exprs) == 7 && c.exprs[6] != "*" {
+		parts = append(parts, "in "+describeCronField(c.exprs[6], "year", nil, false))
+	}
+
+	desc := strings.Join(parts, ", ")
+	return strings.ToUpper(desc[:1]) + desc[1:]
+}
+
+// filterCronSpecials drops L, W and # elements from a field's list
+func filterCronSpecials(expr string) []string {
+	var plain []string
+	for _, part := range strings.Split(expr, ",") {
+		upper := strings.ToUpper(part)
+		if strings.ContainsAny(upper, "#W") || upper == "L" || strings.HasPrefix(upper, "L-") || (len(upper) > 1 && strings.HasSuffix(upper, "L")) {
+			continue
+		}
+		plain = append(plain, part)
+	}
+	return plain
+}
+
+// CronSchedule explains a cron expression and lists its next and previous fire times
+func CronSchedule(ctx context.Context, req *mcp.CallToolRequest, input cronScheduleInput) (*mcp.CallToolResult, *cronScheduleOutput, error) {
+	spec, dialect, notes, err := parseCron(input.Expression, input.Dialect)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid cron expression: %w", err)
+	}
+
+	loc, err := loadLocation(input.Timezone)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	from := time.Now()
+	if input.From != "" {
+		parsed, err := parseTimestamp(input.From, loc)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = parsed.t
+	}
+
+	next := input.Next
+	if next == 0 && input.Previous == 0 {
+		next = defaultCronCount
+	}
+	if next > maxCronCount || input.Previous > maxCronCount {
+		return nil, nil, fmt.Errorf("at most %d fire times can be listed", maxCronCount)
+	}
+
+	zone := loc.String()
+	if loc == time.Local {
+		zone = localZoneName()
+	}
+
+	names := []string{"second", "minute", "hour", "day_of_month", "month", "day_of_week", "year"}
+	fields := make(map[string]string, len(spec.exprs))
+	for i, expr := range spec.exprs {
+		fields[names[i]] = expr
+	}
+
+	output := &cronScheduleOutput{
+		Expression:  strings.Join(spec.exprs, " "),
+		Dialect:     dialect,
+		Fields:      fields,
+		Description: spec.describe(),
+		Timezone:    zone,
+		Next:        spec.fireTimes(from, loc, next, false),
+		Previous:    spec.fireTimes(from, loc, input.Previous, true),
+		Notes:       notes,
+	}
+	if dialect == "standard" {
+		output.Expression = strings.Join(spec.exprs[1:], " ")
+	}
+
+	if next > 0 && len(output.Next) == 0 {
+		output.Notes = append(output.Notes, "the schedule never fires within the next 28 years")
+	}
+
+	return nil, output, nil
+}
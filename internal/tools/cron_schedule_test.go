@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCronFireTimes(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		zone     string
+		from     string
+		backward bool
+		dialect  string
+		want     []string
+	}{
+		{
+			name:    "ranges and steps on weekdays",
+			expr:    "*/15 9-17 * * MON-FRI",
+			zone:    "UTC",
+			from:    "2025-03-07T16:50:00Z",
+			dialect: "standard",
+			want:    []string{"2025-03-07T17:00:00Z", "2025-03-07T17:15:00Z", "2025-03-07T17:30:00Z", "2025-03-07T17:45:00Z", "2025-03-10T09:00:00Z"},
+		},
+		{
+			name:    "day of month and day of week match either",
+			expr:    "0 0 1 * MON",
+			zone:    "UTC",
+			from:    "2025-06-25T00:00:00Z",
+			dialect: "standard",
+			want:    []string{"2025-06-30T00:00:00Z", "2025-07-01T00:00:00Z", "2025-07-07T00:00:00Z"},
+		},
+		{
+			name:    "macro",
+			expr:    "@weekly",
+			zone:    "UTC",
+			from:    "2025-03-05T00:00:00Z",
+			dialect: "standard",
+			want:    []string{"2025-03-09T00:00:00Z", "2025-03-16T00:00:00Z"},
+		},
+		{
+			name:    "seconds field",
+			expr:    "*/20 0 12 * * *",
+			zone:    "UTC",
+			from:    "2025-03-05T12:00:00Z",
+			dialect: "seconds",
+			want:    []string{"2025-03-05T12:00:20Z", "2025-03-05T12:00:40Z", "2025-03-06T12:00:00Z"},
+		},
+		{
+			name:    "last day of month",
+			expr:    "0 0 12 L * ?",
+			zone:    "UTC",
+			from:    "2024-01-15T00:00:00Z",
+			dialect: "quartz",
+			want:    []string{"2024-01-31T12:00:00Z", "2024-02-29T12:00:00Z", "2024-03-31T12:00:00Z"},
+		},
+		{
+			name:    "days before the last day of month",
+			expr:    "0 0 12 L-2 * ?",
+			zone:    "UTC",
+			from:    "2025-02-01T00:00:00Z",
+			dialect: "quartz",
+			want:    []string{"2025-02-26T12:00:00Z", "2025-03-29T12:00:00Z"},
+		},
+		{
+			name:    "last weekday of month",
+			expr:    "0 0 9 LW * ?",
+			zone:    "UTC",
+			from:    "2025-05-01T00:00:00Z",
+			dialect: "quartz",
+			want:    []string{"2025-05-30T09:00:00Z", "2025-06-30T09:00:00Z", "2025-07-31T09:00:00Z"},
+		},
+		{
+			name:    "nearest weekday",
+			expr:    "0 0 9 15W * ?",
+			zone:    "UTC",
+			from:    "2025-03-01T00:00:00Z",
+			dialect: "quartz",
+			want:    []string{"2025-03-14T09:00:00Z", "2025-04-15T09:00:00Z", "2025-05-15T09:00:00Z", "2025-06-16T09:00:00Z"},
+		},
+		{
+			name:    "nearest weekday does not leave the month",
+			expr:    "0 0 9 1W * ?",
+			zone:    "UTC",
+			from:    "2025-01-15T00:00:00Z",
+			dialect: "quartz",
+			want:    []string{"2025-02-03T09:00:00Z", "2025-03-03T09:00:00Z", "2025-04-01T09:00:00Z"},
+		},
+		{
+			name:    "nth weekday of month",
+			expr:    "0 0 10 ? * 6#3",
+			zone:    "UTC",
+			from:    "2025-01-01T00:00:00Z",
+			dialect: "quartz",
+			want:    []string{"2025-01-17T10:00:00Z", "2025-02-21T10:00:00Z", "2025-03-21T10:00:00Z"},
+		},
+		{
+			name:    "last weekday of a kind",
+			expr:    "0 0 10 ? * FRIL",
+			zone:    "UTC",
+			from:    "2025-01-01T00:00:00Z",
+			dialect: "quartz",
+			want:    []string{"2025-01-31T10:00:00Z", "2025-02-28T10:00:00Z", "2025-03-28T10:00:00Z"},
+		},
+		{
+			name:    "quartz year field",
+			expr:    "0 0 0 1 1 ? 2027",
+			zone:    "UTC",
+			from:    "2025-06-01T00:00:00Z",
+			dialect: "quartz",
+			want:    []string{"2027-01-01T00:00:00Z"},
+		},
+		{
+			name:     "previous fire times",
+			expr:     "0 9 * * 1",
+			zone:     "UTC",
+			from:     "2025-03-12T00:00:00Z",
+			backward: true,
+			dialect:  "standard",
+			want:     []string{"2025-03-10T09:00:00Z", "2025-03-03T09:00:00Z"},
+		},
+		{
+			name:    "skipped by spring forward",
+			expr:    "30 2 * * *",
+			zone:    "America/New_York",
+			from:    "2025-03-08T12:00:00Z",
+			dialect: "standard",
+			want:    []string{"2025-03-09T03:00:00-04:00", "2025-03-10T02:30:00-04:00"},
+		},
+		{
+			name:    "repeated by fall back fires once",
+			expr:    "30 1 * * *",
+			zone:    "America/New_York",
+			from:    "2025-11-01T12:00:00Z",
+			dialect: "standard",
+			want:    []string{"2025-11-02T01:30:00-04:00", "2025-11-03T01:30:00-05:00"},
+		},
+		{
+			name:    "repeated by fall back fires twice for hour wildcards",
+			expr:    "30 * * * *",
+			zone:    "America/New_York",
+			from:    "2025-11-02T04:00:00Z",
+			dialect: "standard",
+			want:    []string{"2025-11-02T00:30:00-04:00", "2025-11-02T01:30:00-04:00", "2025-11-02T01:30:00-05:00"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, err := time.LoadLocation(tt.zone)
+			if err != nil {
+				t.Skipf("time zone database not available: %v", err)
+			}
+			from, _ := time.Parse(time.RFC3339, tt.from)
+
+			spec, dialect, _, err := parseCron(tt.expr, "")
+			if err != nil {
+				t.Fatalf("parseCron(%q): %v", tt.expr, err)
+			}
+			if dialect != tt.dialect {
+				t.Errorf("dialect = %q, want %q", dialect, tt.dialect)
+			}
+
+			var got []string
+			for _, f := range spec.fireTimes(from, loc, len(tt.want), tt.backward) {
+				got = append(got, f.Time)
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("fire times =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronDescribe(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: "*/15 9-17 * * MON-FRI", want: "Every 15 minutes, during hours 9 through 17, on Monday through Friday"},
+		{expr: "30 2 * * *", want: "At 02:30"},
+		{expr: "30 * * * *", want: "At minute 30 of every hour"},
+	}
+
+	for _, tt := range tests {
+		spec, _, _, err := parseCron(tt.expr, "")
+		if err != nil {
+			t.Fatalf("parseCron(%q): %v", tt.expr, err)
+		}
+		if got := spec.describe(); got != tt.want {
+			t.Errorf("describe(%q) = %q, want %q", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		dialect string
+	}{
+		{expr: "60 * * * *"},
+		{expr: "* 24 * * *"},
+		{expr: "* * 0 * *"},
+		{expr: "* * * 13 *"},
+		{expr: "* * *"},
+		{expr: "*/0 * * * *"},
+		{expr: "5-1 * * * *"},
+		{expr: "@reboot"},
+		{expr: "@fortnightly"},
+		{expr: "0 0 12 ? * 6#6"},
+		{expr: "0 0 12 * * 6#3", dialect: "quartz"},
+	}
+
+	for _, tt := range tests {
+		if _, _, _, err := parseCron(tt.expr, tt.dialect); err == nil {
+			t.Errorf("parseCron(%q, %q) succeeded, want an error", tt.expr, tt.dialect)
+		}
+	}
+}
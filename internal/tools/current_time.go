@@ -2,17 +2,86 @@ package tools
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+type currentTimeInput struct {
+	Timezones []string `json:"timezones,omitempty" jsonschema:"IANA time zones to report the time in, e.g. 'Europe/Berlin'. Defaults to the server's local zone"`
+	Format    string   `json:"format,omitempty" jsonschema:"Output format: 'rfc3339', 'rfc1123' (default), 'unix', 'unix_ms', a Go reference layout such as '2006-01-02 15:04' or a strftime pattern such as '%Y-%m-%d %H:%M'"`
+}
+
+type zonedTime struct {
+	Timezone      string `json:"timezone" jsonschema:"Time zone name"`
+	Formatted     string `json:"formatted" jsonschema:"Time in the requested format"`
+	ISO           string `json:"iso" jsonschema:"Time in RFC3339 format"`
+	Unix          int64  `json:"unix" jsonschema:"Unix time in seconds"`
+	UnixMillis    int64  `json:"unix_ms" jsonschema:"Unix time in milliseconds"`
+	ZoneName      string `json:"zone_name" jsonschema:"Zone abbreviation in effect, e.g. 'CEST'"`
+	UTCOffset     string `json:"utc_offset" jsonschema:"Offset from UTC, e.g. '+02:00'"`
+	OffsetSeconds int    `json:"offset_seconds" jsonschema:"Offset from UTC in seconds"`
+	IsDST         bool   `json:"is_dst" jsonschema:"Whether daylight saving time is in effect"`
+	Weekday       string `json:"weekday" jsonschema:"Day of the week"`
+	ISOYear       int    `json:"iso_year" jsonschema:"ISO 8601 week-numbering year"`
+	ISOWeek       int    `json:"iso_week" jsonschema:"ISO 8601 week number"`
+	DayOfYear     int    `json:"day_of_year" jsonschema:"Day of the year (1-366)"`
+}
+
 type currentTimeOutput struct {
-	Time string `json:"time" jsonschema:"Current server time in RFC1123 format"`
+	Times []zonedTime `json:"times" jsonschema:"Current time in each requested time zone"`
+}
+
+// describeTime returns the structured representation of t in its location
+func describeTime(t time.Time, zone, format string) zonedTime {
+	abbr, offset := t.Zone()
+	isoYear, isoWeek := t.ISOWeek()
+
+	return zonedTime{
+		Timezone:      zone,
+		Formatted:     formatTime(t, format),
+		ISO:           t.Format(time.RFC3339),
+		Unix:          t.Unix(),
+		UnixMillis:    t.UnixMilli(),
+		ZoneName:      abbr,
+		UTCOffset:     formatUTCOffset(offset),
+		OffsetSeconds: offset,
+		IsDST:         t.IsDST(),
+		Weekday:       t.Weekday().String(),
+		ISOYear:       isoYear,
+		ISOWeek:       isoWeek,
+		DayOfYear:     t.YearDay(),
+	}
 }
 
-func GetCurrentTime(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, *currentTimeOutput, error) {
-	currentTime := fmt.Sprintf("Current server time is: %s", time.Now().Format(time.RFC1123))
-	return nil, &currentTimeOutput{Time: currentTime}, nil
+// GetCurrentTime returns the current time in the requested time zones and format
+func GetCurrentTime(ctx context.Context, req *mcp.CallToolRequest, input currentTimeInput) (*mcp.CallToolResult, *currentTimeOutput, error) {
+	format := input.Format
+	if format == "" {
+		format = "rfc1123"
+	}
+
+	zones := input.Timezones
+	if len(zones) == 0 {
+		zones = []string{"Local"}
+	}
+
+	now := time.Now()
+	times := make([]zonedTime, 0, len(zones))
+
+	for _, zone := range zones {
+		loc, err := loadLocation(zone)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		name := loc.String()
+		if loc == time.Local {
+			name = localZoneName()
+		}
+
+		times = append(times, describeTime(now.In(loc), name, format))
+	}
+
+	return nil, &currentTimeOutput{Times: times}, nil
 }
@@ -0,0 +1,553 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxBusinessDaySpan bounds the range business days are counted over
+const maxBusinessDaySpan = 100 * 366
+
+var (
+	isoDurationPattern   = regexp.MustCompile(`^([+-])?P(?:(\d+)Y)?(?:(\d+)M)?(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+(?:[.,]\d+)?)H)?(?:(\d+(?:[.,]\d+)?)M)?(?:(\d+(?:[.,]\d+)?)S)?)?$`)
+	humanDurationPattern = regexp.MustCompile(`([+-]?\d+(?:\.\d+)?)\s*([a-zµ]+)`)
+)
+
+// durationUnits maps unit names used in human readable durations to a
+// calendar unit ("y", "mo", "d") or a fixed length
+var durationUnits = map[string]struct {
+	calendar string
+	fixed    time.Duration
+	factor   int
+}{
+	"y": {calendar: "y", factor: 1}, "yr": {calendar: "y", factor: 1}, "yrs": {calendar: "y", factor: 1}, "year": {calendar: "y", factor: 1}, "years": {calendar: "y", factor: 1},
+	"mo": {calendar: "mo", factor: 1}, "mon": {calendar: "mo", factor: 1}, "month": {calendar: "mo", factor: 1}, "months": {calendar: "mo", factor: 1},
+	"w": {calendar: "d", factor: 7}, "wk": {calendar: "d", factor: 7}, "week": {calendar: "d", factor: 7}, "weeks": {calendar: "d", factor: 7},
+	"d": {calendar: "d", factor: 1}, "day": {calendar: "d", factor: 1}, "days": {calendar: "d", factor: 1},
+	"h": {fixed: time.Hour}, "hr": {fixed: time.Hour}, "hrs": {fixed: time.Hour}, "hour": {fixed: time.Hour}, "hours": {fixed: time.Hour},
+	"m": {fixed: time.Minute}, "min": {fixed: time.Minute}, "mins": {fixed: time.Minute}, "minute": {fixed: time.Minute}, "minutes": {fixed: time.Minute},
+	"s": {fixed: time.Second}, "sec": {fixed: time.Second}, "secs": {fixed: time.Second}, "second": {fixed: time.Second}, "seconds": {fixed: time.Second},
+	"ms": {fixed: time.Millisecond}, "millisecond": {fixed: time.Millisecond}, "milliseconds": {fixed: time.Millisecond},
+	"us": {fixed: time.Microsecond}, "µs": {fixed: time.Microsecond}, "microsecond": {fixed: time.Microsecond}, "microseconds": {fixed: time.Microsecond},
+	"ns": {fixed: time.Nanosecond}, "nanosecond": {fixed: time.Nanosecond}, "nanoseconds": {fixed: time.Nanosecond},
+}
+
+type dateMathInput struct {
+	Operation  string   `json:"operation" jsonschema:"'add', 'subtract', 'diff', 'business_days' or 'humanize'"`
+	Date       string   `json:"date,omitempty" jsonschema:"Start date in any format accepted by time_convert, or 'now'/'today'. Defaults to now"`
+	End        string   `json:"end,omitempty" jsonschema:"End date for 'diff' and 'business_days'"`
+	Duration   string   `json:"duration,omitempty" jsonschema:"Duration for 'add', 'subtract' and 'humanize': ISO 8601 (P1Y2M3DT4H), Go style (1h30m) or words (3 months 2 days)"`
+	Timezone   string   `json:"timezone,omitempty" jsonschema:"IANA time zone calendar arithmetic is done in, defaults to the server's local zone"`
+	Format     string   `json:"format,omitempty" jsonschema:"Output format of result dates, as for get_current_time. Defaults to rfc3339"`
+	Weekend    []string `json:"weekend,omitempty" jsonschema:"Weekend days for 'business_days', e.g. ['Friday', 'Saturday']. Defaults to Saturday and Sunday"`
+	Holidays   []string `json:"holidays,omitempty" jsonschema:"Holiday dates (YYYY-MM-DD) excluded from business days"`
+	RelativeTo string   `json:"relative_to,omitempty" jsonschema:"Reference time for 'humanize' with a date, defaults to now"`
+}
+
+type dateDifference struct {
+	Years        int     `json:"years" jsonschema:"Whole calendar years"`
+	Months       int     `json:"months" jsonschema:"Whole calendar months after the years"`
+	Days         int     `json:"days" jsonschema:"Whole calendar days after the months"`
+	Hours        int     `json:"hours" jsonschema:"Whole hours after the days"`
+	Minutes      int     `json:"minutes" jsonschema:"Whole minutes after the hours"`
+	Seconds      int     `json:"seconds" jsonschema:"Whole seconds after the minutes"`
+	ISO8601      string  `json:"iso8601" jsonschema:"Calendar difference as an ISO 8601 duration"`
+	TotalMonths  int     `json:"total_months" jsonschema:"Whole calendar months between the dates"`
+	CalendarDays int     `json:"calendar_days" jsonschema:"Number of midnights crossed between the dates"`
+	TotalWeeks   float64 `json:"total_weeks" jsonschema:"Exact difference in weeks"`
+	TotalDays    float64 `json:"total_days" jsonschema:"Exact difference in 24 hour days"`
+	TotalHours   float64 `json:"total_hours" jsonschema:"Exact difference in hours"`
+	TotalMinutes float64 `json:"total_minutes" jsonschema:"Exact difference in minutes"`
+	TotalSeconds float64 `json:"total_seconds" jsonschema:"Exact difference in seconds"`
+	Negative     bool    `json:"negative" jsonschema:"Whether the end is before the start"`
+}
+
+type businessDays struct {
+	BusinessDays int      `json:"business_days" jsonschema:"Working days from the start date (inclusive) to the end date (exclusive)"`
+	CalendarDays int      `json:"calendar_days" jsonschema:"Calendar days in the range"`
+	WeekendDays  int      `json:"weekend_days" jsonschema:"Weekend days in the range"`
+	Holidays     []string `json:"holidays" jsonschema:"Holidays that fell on working days in the range"`
+	Weekend      []string `json:"weekend" jsonschema:"Days treated as the weekend"`
+}
+
+type dateMathOutput struct {
+	Operation    string          `json:"operation" jsonschema:"Operation performed"`
+	Start        *zonedTime      `json:"start,omitempty" jsonschema:"Start date"`
+	End          *zonedTime      `json:"end,omitempty" jsonschema:"End date"`
+	Result       *zonedTime      `json:"result,omitempty" jsonschema:"Result of 'add' or 'subtract'"`
+	Difference   *dateDifference `json:"difference,omitempty" jsonschema:"Difference between start and end"`
+	BusinessDays *businessDays   `json:"business_days,omitempty" jsonschema:"Business day count between start and end"`
+	Humanized    string          `json:"humanized,omitempty" jsonschema:"Human readable form, e.g. '3 days ago'"`
+	Notes        []string        `json:"notes,omitempty" jsonschema:"Notes about clamped month ends and DST transitions"`
+}
+
+// calendarDuration is a duration made of calendar units and a fixed part
+type calendarDuration struct {
+	Years, Months, Days int
+	Clock               time.Duration
+}
+
+// negate returns the duration with every component negated
+func (d calendarDuration) negate() calendarDuration {
+	return calendarDuration{Years: -d.Years, Months: -d.Months, Days: -d.Days, Clock: -d.Clock}
+}
+
+// parseFraction parses an ISO 8601 number, which may use ',' as decimal separator
+func parseFraction(s string) float64 {
+	f, _ := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64)
+	return f
+}
+
+// parseCalendarDuration parses an ISO 8601, Go style or human readable duration
+func parseCalendarDuration(s string) (calendarDuration, error) {
+	var d calendarDuration
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return d, fmt.Errorf("duration is required")
+	}
+
+	if m := isoDurationPattern.FindStringSubmatch(strings.ToUpper(s)); m != nil && s != "P" && !strings.HasSuffix(strings.ToUpper(s), "T") {
+		atoi := func(v string) int { n, _ := strconv.Atoi(v); return n }
+		d.Years, d.Months, d.Days = atoi(m[2]), atoi(m[3]), atoi(m[4])*7+atoi(m[5])
+		d.Clock = time.Duration(parseFraction(m[6])*float64(time.Hour) +
+			parseFraction(m[7])*float64(time.Minute) +
+			parseFraction(m[8])*float64(time.Second))
+		if m[1] == "-" {
+			d = d.negate()
+		}
+		return d, nil
+	}
+
+	if clock, err := time.ParseDuration(s); err == nil {
+		d.Clock = clock
+		return d, nil
+	}
+
+	text := strings.ToLower(s)
+	negative := false
+	if rest, ok := strings.CutPrefix(text, "-"); ok {
+		negative, text = true, rest
+	}
+	text = strings.NewReplacer(",", " ", " and ", " ").Replace(text)
+
+	matches := humanDurationPattern.FindAllStringSubmatchIndex(text, -1)
+	if matches == nil {
+		return d, fmt.Errorf("invalid duration '%s', expected ISO 8601 (P1Y2M3DT4H), Go style (1h30m) or words (3 months 2 days)", s)
+	}
+
+	if rest := humanDurationPattern.ReplaceAllString(text, ""); strings.TrimSpace(rest) != "" {
+		return d, fmt.Errorf("invalid duration '%s', unexpected '%s'", s, strings.TrimSpace(rest))
+	}
+
+	for _, m := range matches {
+		value, _ := strconv.ParseFloat(text[m[2]:m[3]], 64)
+		unitName := text[m[4]:m[5]]
+
+		unit, ok := durationUnits[unitName]
+		if !ok {
+			return d, fmt.Errorf("unknown duration unit '%s' in '%s'", unitName, s)
+		}
+
+		if unit.calendar != "" {
+			if value != math.Trunc(value) {
+				return d, fmt.Errorf("'%s %s' must be a whole number of %s", text[m[2]:m[3]], unitName, unitName)
+			}
+			switch unit.calendar {
+			case "y":
+				d.Years += int(value)
+			case "mo":
+				d.Months += int(value)
+			case "d":
+				d.Days += int(value) * unit.factor
+			}
+			continue
+		}
+
+		d.Clock += time.Duration(value * float64(unit.fixed))
+	}
+
+	if negative {
+		d = d.negate()
+	}
+	return d, nil
+}
+
+// addCalendarDuration adds d to t in loc. Years and months clamp to the end
+// of the month, days keep the wall clock time and the fixed part is exact.
+func addCalendarDuration(t time.Time, d calendarDuration, loc *time.Location) (time.Time, []string) {
+	var notes []string
+	t = t.In(loc)
+
+	y, mo, day := t.Date()
+	h, mi, s := t.Clock()
+
+	total := int(mo) - 1 + d.Months + d.Years*12
+	y += total / 12
+	total %= 12
+	if total < 0 {
+		total += 12
+		y--
+	}
+	mo = time.Month(total + 1)
+
+	if last := lastDayOfMonth(y, mo); day > last {
+		notes = append(notes, fmt.Sprintf("day %d does not exist in %s %d; clamped to the last day of the month (%d)", day, mo, y, last))
+		day = last
+	}
+
+	wall := time.Date(y, mo, day+d.Days, h, mi, s, t.Nanosecond(), time.UTC)
+	result, dst := resolveLocalTime(wall, loc)
+	if dst != nil && (d.Years != 0 || d.Months != 0 || d.Days != 0) {
+		notes = append(notes, dst.Explanation)
+	}
+
+	return result.Add(d.Clock), notes
+}
+
+// addMonthsClamped adds months to t like addCalendarDuration
+func addMonthsClamped(t time.Time, months int, loc *time.Location) time.Time {
+	result, _ := addCalendarDuration(t, calendarDuration{Months: months}, loc)
+	return result
+}
+
+// calendarDifference breaks the span from start to end into calendar units
+func calendarDifference(start, end time.Time, loc *time.Location) *dateDifference {
+	diff := &dateDifference{}
+	exact := end.Sub(start)
+
+	if end.Before(start) {
+		start, end = end, start
+		diff.Negative = true
+	}
+	start, end = start.In(loc), end.In(loc)
+
+	months := (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+	for months > 0 && addMonthsClamped(start, months, loc).After(end) {
+		months--
+	}
+	anchor := addMonthsClamped(start, months, loc)
+
+	days := int(end.Sub(anchor).Hours() / 24)
+	for days > 0 && anchor.AddDate(0, 0, days).After(end) {
+		days--
+	}
+	for !anchor.AddDate(0, 0, days+1).After(end) {
+		days++
+	}
+	rest := end.Sub(anchor.AddDate(0, 0, days))
+
+	diff.TotalMonths = months
+	diff.Years, diff.Months, diff.Days = months/12, months%12, days
+	diff.Hours = int(rest / time.Hour)
+	diff.Minutes = int(rest % time.Hour / time.Minute)
+	diff.Seconds = int(rest % time.Minute / time.Second)
+
+	sy, sm, sd := start.Date()
+	ey, em, ed := end.Date()
+	diff.CalendarDays = int(math.Round(time.Date(ey, em, ed, 0, 0, 0, 0, time.UTC).Sub(time.Date(sy, sm, sd, 0, 0, 0, 0, time.UTC)).Hours() / 24))
+
+	diff.TotalSeconds = exact.Seconds()
+	diff.TotalMinutes = exact.Minutes()
+	diff.TotalHours = exact.Hours()
+	diff.TotalDays = exact.Hours() / 24
+	diff.TotalWeeks = exact.Hours() / (24 * 7)
+
+	diff.ISO8601 = formatISODuration(diff)
+
+	return diff
+}
+
+// formatISODuration formats a calendar difference as an ISO 8601 duration
+func formatISODuration(d *dateDifference) string {
+	var b strings.Builder
+	if d.Negative {
+		b.WriteByte('-')
+	}
+	b.WriteByte('P')
+	for _, part := range []struct {
+		n    int
+		unit string
+	}{{d.Years, "Y"}, {d.Months, "M"}, {d.Days, "D"}} {
+		if part.n != 0 {
+			fmt.Fprintf(&b, "%d%s", part.n, part.unit)
+		}
+	}
+	if d.Hours != 0 || d.Minutes != 0 || d.Seconds != 0 {
+		b.WriteByte('T')
+		for _, part := range []struct {
+			n    int
+			unit string
+		}{{d.Hours, "H"}, {d.Minutes, "M"}, {d.Seconds, "S"}} {
+			if part.n != 0 {
+				fmt.Fprintf(&b, "%d%s", part.n, part.unit)
+			}
+		}
+	}
+	if b.Len() <= 2 && strings.HasSuffix(b.String(), "P") {
+		return "PT0S"
+	}
+	return b.String()
+}
+
+// humanizeDuration describes a span like "3 days", rounding to the largest unit
+func humanizeDuration(d time.Duration) string {
+	seconds := math.Abs(d.Seconds())
+	unit := func(n float64, name string) string {
+		v := int(math.Round(n))
+		if v == 1 {
+			if name == "hour" {
+				return "an hour"
+			}
+			return "a " + name
+		}
+		return fmt.Sprintf("%d %ss", v, name)
+	}
+
+	switch {
+	case seconds < 45:
+		return "a few seconds"
+	case seconds < 45*60:
+		return unit(seconds/60, "minute")
+	case seconds < 22*3600:
+		return unit(seconds/3600, "hour")
+	case seconds < 26*86400:
+		return unit(seconds/86400, "day")
+	case seconds < 320*86400:
+		return unit(seconds/(30.436875*86400), "month")
+	}
+	return unit(seconds/(365.2425*86400), "year")
+}
+
+// humanizeRelative describes t relative to ref, e.g. "3 days ago" or "in 2 hours"
+func humanizeRelative(t, ref time.Time) string {
+	d := t.Sub(ref)
+	if d < 0 {
+		return humanizeDuration(d) + " ago"
+	}
+	return "in " + humanizeDuration(d)
+}
+
+// parseWeekdays parses day names such as "Sat" or "saturday"
+func parseWeekdays(names []string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	for _, name := range names {
+		found := false
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			full := strings.ToLower(wd.String())
+			n := strings.ToLower(strings.TrimSpace(name))
+			if n == full || (len(n) >= 2 && strings.HasPrefix(full, n)) {
+				days[wd] = true
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown weekday '%s'", name)
+		}
+	}
+	return days, nil
+}
+
+// countBusinessDays counts working days from start (inclusive) to end (exclusive)
+func countBusinessDays(start, end time.Time, weekend map[time.Weekday]bool, holidays map[string]bool) (*businessDays, error) {
+	sy, sm, sd := start.Date()
+	ey, em, ed := end.Date()
+	from := time.Date(sy, sm, sd, 0, 0, 0, 0, time.UTC)
+	to := time.Date(ey, em, ed, 0, 0, 0, 0, time.UTC)
+
+	step := 1
+	if to.Before(from) {
+		from, to = to, from
+		step = -1
+	}
+
+	span := int(to.Sub(from).Hours() / 24)
+	if span > maxBusinessDaySpan {
+		return nil, fmt.Errorf("business days can be counted over at most %d days", maxBusinessDaySpan)
+	}
+
+	result := &businessDays{CalendarDays: span * step, Holidays: []string{}, Weekend: []string{}}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if weekend[wd] {
+			result.Weekend = append(result.Weekend, wd.String())
+		}
+	}
+
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		switch {
+		case weekend[d.Weekday()]:
+			result.WeekendDays++
+		case holidays[d.Format(time.DateOnly)]:
+			result.Holidays = append(result.Holidays, d.Format(time.DateOnly))
+		default:
+			result.BusinessDays++
+		}
+	}
+	result.BusinessDays *= step
+
+	return result, nil
+}
+
+// parseDateArg parses a date argument, accepting "now" and "today"
+func parseDateArg(s string, loc *time.Location, now time.Time) (time.Time, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "now":
+		return now.In(loc), nil
+	case "today":
+		y, m, d := now.In(loc).Date()
+		t, _ := resolveLocalTime(time.Date(y, m, d, 0, 0, 0, 0, time.UTC), loc)
+		return t, nil
+	}
+
+	parsed, err := parseTimestamp(s, loc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed.t.In(loc), nil
+}
+
+// DateMath adds durations to dates, computes differences, counts business days and humanizes durations
+func DateMath(ctx context.Context, req *mcp.CallToolRequest, input dateMathInput) (*mcp.CallToolResult, *dateMathOutput, error) {
+	loc, err := loadLocation(input.Timezone)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	zone := loc.String()
+	if loc == time.Local {
+		zone = localZoneName()
+	}
+
+	format := input.Format
+	if format == "" {
+		format = "rfc3339"
+	}
+
+	now := time.Now()
+	output := &dateMathOutput{Operation: strings.ToLower(input.Operation)}
+
+	describe := func(t time.Time) *zonedTime {
+		zt := describeTime(t, zone, format)
+		return &zt
+	}
+
+	switch output.Operation {
+	case "add", "subtract":
+		start, err := parseDateArg(input.Date, loc, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		d, err := parseCalendarDuration(input.Duration)
+		if err != nil {
+			return nil, nil, err
+		}
+		if output.Operation == "subtract" {
+			d = d.negate()
+		}
+
+		result, notes := addCalendarDuration(start, d, loc)
+		output.Start = describe(start)
+		output.Result = describe(result)
+		output.Humanized = humanizeDuration(result.Sub(start)) + " after the start date"
+		if result.Before(start) {
+			output.Humanized = humanizeDuration(result.Sub(start)) + " before the start date"
+		}
+		output.Notes = notes
+
+	case "diff":
+		if input.End == "" {
+			return nil, nil, fmt.Errorf("end is required for 'diff'")
+		}
+		start, err := parseDateArg(input.Date, loc, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		end, err := parseDateArg(input.End, loc, now)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output.Start = describe(start)
+		output.End = describe(end)
+		output.Difference = calendarDifference(start, end, loc)
+		output.Humanized = humanizeDuration(end.Sub(start))
+
+	case "business_days":
+		if input.End == "" {
+			return nil, nil, fmt.Errorf("end is required for 'business_days'")
+		}
+		start, err := parseDateArg(input.Date, loc, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		end, err := parseDateArg(input.End, loc, now)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		weekendNames := input.Weekend
+		if len(weekendNames) == 0 {
+			weekendNames = []string{"Saturday", "Sunday"}
+		}
+		weekend, err := parseWeekdays(weekendNames)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		holidays := make(map[string]bool, len(input.Holidays))
+		for _, h := range input.Holidays {
+			day, err := time.Parse(time.DateOnly, strings.TrimSpace(h))
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid holiday '%s', expected YYYY-MM-DD", h)
+			}
+			holidays[day.Format(time.DateOnly)] = true
+		}
+
+		output.Start = describe(start)
+		output.End = describe(end)
+		output.BusinessDays, err = countBusinessDays(start, end, weekend, holidays)
+		if err != nil {
+			return nil, nil, err
+		}
+
+	case "humanize":
+		if input.Duration != "" {
+			d, err := parseCalendarDuration(input.Duration)
+			if err != nil {
+				return nil, nil, err
+			}
+			// Measure calendar units from the reference time so months have their real length
+			ref, err := parseDateArg(input.RelativeTo, loc, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			end, _ := addCalendarDuration(ref, d, loc)
+			output.Humanized = humanizeDuration(end.Sub(ref))
+			break
+		}
+
+		t, err := parseDateArg(input.Date, loc, now)
+		if err != nil {
+			return nil, nil, err
+		}
+		ref, err := parseDateArg(input.RelativeTo, loc, now)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output.Start = describe(t)
+		output.Humanized = humanizeRelative(t, ref)
+
+	default:
+		return nil, nil, fmt.Errorf("unknown operation '%s', expected 'add', 'subtract', 'diff', 'business_days' or 'humanize'", input.Operation)
+	}
+
+	return nil, output, nil
+}
@@ -0,0 +1,347 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// dirURIPrefix is the scheme of the resources describing watched directories
+	dirURIPrefix = "dir://"
+
+	dirWatchDebounce     = 500 * time.Millisecond
+	dirWatchMaxDelay     = 5 * time.Second
+	dirWatchPollInterval = 2 * time.Second
+	dirWatchRecentEvents = 100
+	dirWatchMaxEntries   = 500
+)
+
+// dirChange is one change to a watched directory
+type dirChange struct {
+	Time time.Time `json:"time"`
+	Op   string    `json:"op"`
+	Name string    `json:"name,omitempty"`
+}
+
+type dirWatchEntry struct {
+	Name           string    `json:"name"`
+	IsDir          bool      `json:"is_dir"`
+	Size           int64     `json:"size"`
+	LastModifyTime time.Time `json:"last_modify"`
+}
+
+type dirWatchState struct {
+	URI           string          `json:"uri"`
+	Path          string          `json:"path"`
+	Watching      bool            `json:"watching"`
+	Backend       string          `json:"backend,omitempty"`
+	LastChange    *time.Time      `json:"last_change,omitempty"`
+	RecentChanges []dirChange     `json:"recent_changes"`
+	Entries       []dirWatchEntry `json:"entries"`
+	Truncated     bool            `json:"truncated,omitempty"`
+}
+
+// dirWatch watches one directory for the sessions subscribed to its URI
+type dirWatch struct {
+	uri         string
+	path        string
+	backend     string
+	stop        func()
+	subscribers map[*mcp.ServerSession]bool
+
+	pending  []dirChange
+	debounce *time.Timer
+	firstAt  time.Time
+	recent   []dirChange
+}
+
+// DirWatchers watches directories while clients are subscribed to their dir://
+// resources and notifies them, debounced, when entries are added, modified or removed
+type DirWatchers struct {
+	server   *mcp.Server
+	mu       sync.Mutex
+	watches  map[string]*dirWatch
+	sessions map[*mcp.ServerSession]bool
+}
+
+// NewDirWatchers creates the directory watch registry for a server. Pass it to
+// SubscribeResource and UnsubscribeResource to route dir:// subscriptions to it.
+func NewDirWatchers(server *mcp.Server) *DirWatchers {
+	return &DirWatchers{server: server, watches: make(map[string]*dirWatch), sessions: make(map[*mcp.ServerSession]bool)}
+}
+
+// dirURIPath returns the directory a dir:// URI names, e.g. dir://~/Downloads or dir:///tmp
+func dirURIPath(uri string) (string, error) {
+	path := strings.TrimPrefix(uri, dirURIPrefix)
+	if path == "" {
+		return "", fmt.Errorf("invalid directory URI %s", uri)
+	}
+
+	path, err := expandHome(path)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("directory URIs need an absolute path or one starting with '~', got %s", uri)
+	}
+	return filepath.Clean(path), nil
+}
+
+// snapshotDir lists the entries of a directory by name
+func snapshotDir(path string) (map[string]dirWatchEntry, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := make(map[string]dirWatchEntry, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		snapshot[e.Name()] = dirWatchEntry{Name: e.Name(), IsDir: e.IsDir(), Size: info.Size(), LastModifyTime: info.ModTime()}
+	}
+	return snapshot, nil
+}
+
+// startPollingWatch compares directory snapshots periodically until stop is called
+func startPollingWatch(path string, notify func(dirChange)) (func(), error) {
+	previous, err := snapshotDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(dirWatchPollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			current, err := snapshotDir(path)
+			if err != nil {
+				if previous != nil {
+					notify(dirChange{Op: "removed"})
+				}
+				previous = nil
+				continue
+			}
+			for name, e := range current {
+				old, ok := previous[name]
+				switch {
+				case !ok:
+					notify(dirChange{Op: "added", Name: name})
+				case old.Size != e.Size || !old.LastModifyTime.Equal(e.LastModifyTime):
+					notify(dirChange{Op: "modified", Name: name})
+				}
+			}
+			for name := range previous {
+				if _, ok := current[name]; !ok {
+					notify(dirChange{Op: "removed", Name: name})
+				}
+			}
+			previous = current
+		}
+	}()
+
+	return func() { close(done) }, nil
+}
+
+// record queues a change and schedules a debounced notification
+func (r *DirWatchers) record(w *dirWatch, change dirChange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.watches[w.uri] != w {
+		return
+	}
+
+	change.Time = time.Now()
+	if len(w.pending) == 0 {
+		w.firstAt = change.Time
+	}
+	// A single write raises several events, keep one per run
+	if n := len(w.pending); n > 0 && w.pending[n-1].Op == change.Op && w.pending[n-1].Name == change.Name {
+		w.pending[n-1].Time = change.Time
+	} else {
+		w.pending = append(w.pending, change)
+	}
+
+	// Wait for a quiet period, but never hold back a busy directory for longer than dirWatchMaxDelay
+	delay := min(dirWatchDebounce, dirWatchMaxDelay-change.Time.Sub(w.firstAt))
+	if w.debounce == nil {
+		w.debounce = time.AfterFunc(delay, func() { r.flush(w) })
+	} else {
+		w.debounce.Reset(delay)
+	}
+}
+
+// flush moves pending changes to the recent list and notifies subscribers
+func (r *DirWatchers) flush(w *dirWatch) {
+	r.mu.Lock()
+	if r.watches[w.uri] != w || len(w.pending) == 0 {
+		r.mu.Unlock()
+		return
+	}
+	w.recent = append(w.recent, w.pending...)
+	if len(w.recent) > dirWatchRecentEvents {
+		w.recent = w.recent[len(w.recent)-dirWatchRecentEvents:]
+	}
+	w.pending = nil
+	r.mu.Unlock()
+
+	if err := r.server.ResourceUpdated(context.Background(), &mcp.ResourceUpdatedNotificationParams{URI: w.uri}); err != nil {
+		log.Printf("failed to send resource update: %v", err)
+	}
+}
+
+// stopWatch stops a watch. The caller must hold the lock.
+func (r *DirWatchers) stopWatch(w *dirWatch) {
+	w.stop()
+	if w.debounce != nil {
+		w.debounce.Stop()
+	}
+	delete(r.watches, w.uri)
+}
+
+// subscribe starts watching the directory of a dir:// URI for a session
+func (r *DirWatchers) subscribe(ss *mcp.ServerSession, uri string) error {
+	path, err := dirURIPath(uri)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", path)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.watchSession(ss)
+
+	if w, ok := r.watches[uri]; ok {
+		w.subscribers[ss] = true
+		return nil
+	}
+
+	w := &dirWatch{uri: uri, path: path, subscribers: map[*mcp.ServerSession]bool{ss: true}}
+	notify := func(c dirChange) { r.record(w, c) }
+
+	w.stop, w.backend, err = startNativeWatch(path, notify)
+	if err != nil {
+		if w.stop, err = startPollingWatch(path, notify); err != nil {
+			return err
+		}
+		w.backend = "polling"
+	}
+
+	r.watches[uri] = w
+	return nil
+}
+
+// unsubscribe stops watching for a session, and stops the watch when nobody is left
+func (r *DirWatchers) unsubscribe(ss *mcp.ServerSession, uri string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w, ok := r.watches[uri]
+	if !ok {
+		return
+	}
+	delete(w.subscribers, ss)
+	if len(w.subscribers) == 0 {
+		r.stopWatch(w)
+	}
+}
+
+// watchSession drops the subscriptions of a session once it closes. The caller must hold the lock.
+func (r *DirWatchers) watchSession(ss *mcp.ServerSession) {
+	if r.sessions[ss] {
+		return
+	}
+	r.sessions[ss] = true
+
+	go func() {
+		_ = ss.Wait()
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, w := range r.watches {
+			delete(w.subscribers, ss)
+			if len(w.subscribers) == 0 {
+				r.stopWatch(w)
+			}
+		}
+		delete(r.sessions, ss)
+	}()
+}
+
+// ReadResource returns the entries and recent changes of the directory named by a dir:// URI
+func (r *DirWatchers) ReadResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	path, err := dirURIPath(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := snapshotDir(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	state := dirWatchState{URI: uri, Path: path, RecentChanges: []dirChange{}, Entries: []dirWatchEntry{}}
+
+	r.mu.Lock()
+	if w, ok := r.watches[uri]; ok {
+		state.Watching = true
+		state.Backend = w.backend
+		state.RecentChanges = append(state.RecentChanges, w.recent...)
+		if len(w.recent) > 0 {
+			last := w.recent[len(w.recent)-1].Time
+			state.LastChange = &last
+		}
+	}
+	r.mu.Unlock()
+
+	for _, e := range snapshot {
+		state.Entries = append(state.Entries, e)
+	}
+	// Newest first, so fresh downloads and build artifacts are on top
+	sort.Slice(state.Entries, func(i, j int) bool {
+		return state.Entries[i].LastModifyTime.After(state.Entries[j].LastModifyTime)
+	})
+	if len(state.Entries) > dirWatchMaxEntries {
+		state.Entries = state.Entries[:dirWatchMaxEntries]
+		state.Truncated = true
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode directory: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: "application/json", Text: string(data)}},
+	}, nil
+}
@@ -0,0 +1,65 @@
+//go:build linux
+
+package tools
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_MODIFY | syscall.IN_CLOSE_WRITE |
+	syscall.IN_ATTRIB | syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+
+// startNativeWatch watches the entries of a directory with inotify until stop is called
+func startNativeWatch(path string, notify func(dirChange)) (stop func(), backend string, err error) {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to initialize inotify: %w", err)
+	}
+	if _, err := syscall.InotifyAddWatch(fd, path, inotifyMask); err != nil {
+		syscall.Close(fd)
+		return nil, "", fmt.Errorf("failed to watch %s: %w", path, err)
+	}
+
+	// A non-blocking descriptor goes through the runtime poller, so Close unblocks Read
+	f := os.NewFile(uintptr(fd), "inotify")
+
+	go func() {
+		buf := make([]byte, 64*1024)
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+
+			for offset := 0; offset+syscall.SizeofInotifyEvent <= n; {
+				var event syscall.InotifyEvent
+				if err := binary.Read(bytes.NewReader(buf[offset:offset+syscall.SizeofInotifyEvent]), binary.NativeEndian, &event); err != nil {
+					break
+				}
+				nameStart := offset + syscall.SizeofInotifyEvent
+				nameEnd := min(nameStart+int(event.Len), n)
+				name := string(bytes.TrimRight(buf[nameStart:nameEnd], "\x00"))
+				offset = nameEnd
+
+				switch {
+				case event.Mask&syscall.IN_Q_OVERFLOW != 0:
+					notify(dirChange{Op: "overflow"})
+				case event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0:
+					notify(dirChange{Op: "added", Name: name})
+				case event.Mask&(syscall.IN_DELETE|syscall.IN_MOVED_FROM) != 0:
+					notify(dirChange{Op: "removed", Name: name})
+				case event.Mask&(syscall.IN_MODIFY|syscall.IN_CLOSE_WRITE|syscall.IN_ATTRIB) != 0:
+					notify(dirChange{Op: "modified", Name: name})
+				case event.Mask&(syscall.IN_DELETE_SELF|syscall.IN_MOVE_SELF) != 0:
+					notify(dirChange{Op: "removed"})
+				}
+			}
+		}
+	}()
+
+	return func() { f.Close() }, "inotify", nil
+}
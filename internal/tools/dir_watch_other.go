@@ -0,0 +1,10 @@
+//go:build !linux
+
+package tools
+
+import "errors"
+
+// startNativeWatch reports that there is no native watcher, so polling is used
+func startNativeWatch(path string, notify func(dirChange)) (stop func(), backend string, err error) {
+	return nil, "", errors.ErrUnsupported
+}
@@ -0,0 +1,374 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultDiskUsageTopN = 10
+	maxDiskUsageTopN     = 100
+	maxDiskUsageErrors   = 100
+)
+
+type diskUsageInput struct {
+	Path           string   `json:"path,omitempty" jsonschema:"Directory to analyze, '~' is expanded. Defaults to the home directory"`
+	TopN           int      `json:"top_n,omitempty" jsonschema:"Number of largest directories, files and extensions to return, defaults to 10 (at most 100)"`
+	Exclude        []string `json:"exclude,omitempty" jsonschema:"Patterns in .gitignore syntax to skip, e.g. 'node_modules/', '*.log', '/build', '!keep.log'"`
+	UseGitignore   bool     `json:"use_gitignore,omitempty" jsonschema:"Also honour .gitignore files found in the tree"`
+	CrossDevices   bool     `json:"cross_devices,omitempty" jsonschema:"Descend into other filesystems mounted below the path"`
+	FollowSymlinks bool     `json:"follow_symlinks,omitempty" jsonschema:"Follow symbolic links; loops and directories reached twice are counted once"`
+	Workers        int      `json:"workers,omitempty" jsonschema:"Number of directories read concurrently, defaults to the number of CPUs"`
+}
+
+type diskUsageDir struct {
+	Path  string `json:"path" jsonschema:"Directory path"`
+	Size  int64  `json:"size" jsonschema:"Total size of the files below the directory in bytes"`
+	Files int64  `json:"files" jsonschema:"Number of files below the directory"`
+}
+
+type diskUsageFile struct {
+	Path           string    `json:"path" jsonschema:"File path"`
+	Size           int64     `json:"size" jsonschema:"Size in bytes"`
+	LastModifyTime time.Time `json:"last_modify" jsonschema:"Last modify time of the file"`
+}
+
+type diskUsageBucket struct {
+	Name  string `json:"name" jsonschema:"Extension (empty for files without one) or category"`
+	Files int64  `json:"files" jsonschema:"Number of files"`
+	Size  int64  `json:"size" jsonschema:"Total size in bytes"`
+}
+
+type diskUsageOutput struct {
+	Path               string            `json:"path" jsonschema:"Directory that was analyzed"`
+	TotalSize          int64             `json:"total_size" jsonschema:"Total apparent size of all files in bytes"`
+	Files              int64             `json:"files" jsonschema:"Number of files"`
+	Directories        int64             `json:"directories" jsonschema:"Number of directories, excluding the path itself"`
+	LargestDirectories []diskUsageDir    `json:"largest_directories" jsonschema:"Largest directories below the path"`
+	LargestFiles       []diskUsageFile   `json:"largest_files" jsonschema:"Largest files"`
+	ByExtension        []diskUsageBucket `json:"by_extension" jsonschema:"Extensions taking the most space"`
+	ByCategory         []diskUsageBucket `json:"by_category" jsonschema:"Space per file category (images, videos, audio, documents, archives, installers, code, fonts, other)"`
+	Excluded           int64             `json:"excluded" jsonschema:"Entries skipped by exclude patterns or .gitignore files"`
+	OtherFilesystems   int64             `json:"other_filesystems" jsonschema:"Mount points skipped because they are on another filesystem"`
+	SymlinksSkipped    int64             `json:"symlinks_skipped" jsonschema:"Symbolic links not followed, or leading to a loop or an already counted directory"`
+	HardLinks          int64             `json:"hard_links" jsonschema:"Additional hard links to files already counted"`
+	Elapsed            string            `json:"elapsed" jsonschema:"Time the scan took"`
+	Errors             []string          `json:"errors,omitempty" jsonschema:"Paths that could not be read (at most 100)"`
+}
+
+// diskUsageScan holds the shared state of a concurrent disk usage walk
+type diskUsageScan struct {
+	ctx     context.Context
+	input   diskUsageInput
+	rootDev uint64
+	hasDev  bool
+	topN    int
+	sem     chan struct{}
+
+	mu          sync.Mutex
+	out         *diskUsageOutput
+	dirs        []diskUsageDir
+	files       []diskUsageFile // largest files, sorted by size descending
+	byExt       map[string]*diskUsageBucket
+	byCategory  map[string]*diskUsageBucket
+	seenFiles   map[[2]uint64]bool
+	visitedDirs map[string]bool
+}
+
+func (s *diskUsageScan) addError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.out.Errors) < maxDiskUsageErrors {
+		s.out.Errors = append(s.out.Errors, err.Error())
+	}
+}
+
+// firstVisit records a directory and reports whether it was not seen before
+func (s *diskUsageScan) firstVisit(path string, info os.FileInfo) bool {
+	key := path
+	if dev, ino, ok := fileID(info); ok {
+		key = fmt.Sprintf("%d:%d", dev, ino)
+	} else if real, err := filepath.EvalSymlinks(path); err == nil {
+		key = real
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.visitedDirs[key] {
+		return false
+	}
+	s.visitedDirs[key] = true
+	return true
+}
+
+// addFile counts a regular file, returning its size and false for repeated hard links
+func (s *diskUsageScan) addFile(path string, info os.FileInfo) (int64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if dev, ino, ok := fileID(info); ok {
+		key := [2]uint64{dev, ino}
+		if s.seenFiles[key] {
+			s.out.HardLinks++
+			return 0, false
+		}
+		s.seenFiles[key] = true
+	}
+
+	size := info.Size()
+	s.out.Files++
+	s.out.TotalSize += size
+
+	ext := fileExtension(info.Name())
+	for _, b := range []struct {
+		m   map[string]*diskUsageBucket
+		key string
+	}{{s.byExt, ext}, {s.byCategory, categoryForExtension(ext)}} {
+		bucket, ok := b.m[b.key]
+		if !ok {
+			bucket = &diskUsageBucket{Name: b.key}
+			b.m[b.key] = bucket
+		}
+		bucket.Files++
+		bucket.Size += size
+	}
+
+	// Keep the top N largest files in a small sorted slice
+	if len(s.files) < s.topN || size > s.files[len(s.files)-1].Size {
+		i := sort.Search(len(s.files), func(i int) bool { return s.files[i].Size < size })
+		s.files = append(s.files, diskUsageFile{})
+		copy(s.files[i+1:], s.files[i:])
+		s.files[i] = diskUsageFile{Path: path, Size: size, LastModifyTime: info.ModTime()}
+		if len(s.files) > s.topN {
+			s.files = s.files[:s.topN]
+		}
+	}
+
+	return size, true
+}
+
+// walk sums the directory at path, reading subdirectories concurrently when a worker is free
+func (s *diskUsageScan) walk(path, rel string, matcher ignoreMatcher) (int64, int64) {
+	if s.ctx.Err() != nil {
+		return 0, 0
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		s.addError(err)
+		return 0, 0
+	}
+
+	if s.input.UseGitignore {
+		if rules, err := readIgnoreFile(filepath.Join(path, ".gitignore"), rel); err == nil {
+			matcher = matcher.with(rules)
+		} else if !os.IsNotExist(err) {
+			s.addError(err)
+		}
+	}
+
+	var size, files int64
+	var wg sync.WaitGroup
+	var childMu sync.Mutex
+
+	for _, entry := range entries {
+		if s.ctx.Err() != nil {
+			break
+		}
+
+		childPath := filepath.Join(path, entry.Name())
+		childRel := entry.Name()
+		if rel != "" {
+			childRel = rel + "/" + entry.Name()
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			s.addError(err)
+			continue
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			if !s.input.FollowSymlinks {
+				s.mu.Lock()
+				s.out.SymlinksSkipped++
+				s.mu.Unlock()
+				continue
+			}
+			if info, err = os.Stat(childPath); err != nil {
+				s.addError(err)
+				continue
+			}
+		}
+
+		if matcher.ignored(childRel, info.IsDir()) {
+			s.mu.Lock()
+			s.out.Excluded++
+			s.mu.Unlock()
+			continue
+		}
+
+		if !info.IsDir() {
+			if info.Mode().IsRegular() {
+				if fileSize, counted := s.addFile(childPath, info); counted {
+					childMu.Lock()
+					size += fileSize
+					files++
+					childMu.Unlock()
+				}
+			}
+			continue
+		}
+
+		if s.hasDev && !s.input.CrossDevices {
+			if dev, _, ok := fileID(info); ok && dev != s.rootDev {
+				s.mu.Lock()
+				s.out.OtherFilesystems++
+				s.mu.Unlock()
+				continue
+			}
+		}
+
+		if s.input.FollowSymlinks && !s.firstVisit(childPath, info) {
+			s.mu.Lock()
+			s.out.SymlinksSkipped++
+			s.mu.Unlock()
+			continue
+		}
+
+		s.mu.Lock()
+		s.out.Directories++
+		s.mu.Unlock()
+
+		descend := func() {
+			childSize, childFiles := s.walk(childPath, childRel, matcher)
+			s.mu.Lock()
+			s.dirs = append(s.dirs, diskUsageDir{Path: childPath, Size: childSize, Files: childFiles})
+			s.mu.Unlock()
+
+			childMu.Lock()
+			size += childSize
+			files += childFiles
+			childMu.Unlock()
+		}
+
+		// Hand the directory to another goroutine if a worker slot is free, otherwise walk it here
+		select {
+		case s.sem <- struct{}{}:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-s.sem }()
+				descend()
+			}()
+		default:
+			descend()
+		}
+	}
+
+	wg.Wait()
+	return size, files
+}
+
+// topBuckets returns the buckets of m ordered by size, at most n of them when n > 0
+func topBuckets(m map[string]*diskUsageBucket, n int) []diskUsageBucket {
+	buckets := make([]diskUsageBucket, 0, len(m))
+	for _, b := range m {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Size != buckets[j].Size {
+			return buckets[i].Size > buckets[j].Size
+		}
+		return buckets[i].Name < buckets[j].Name
+	})
+	if n > 0 && len(buckets) > n {
+		buckets = buckets[:n]
+	}
+	return buckets
+}
+
+// DiskUsage reports what takes up space below a directory
+func DiskUsage(ctx context.Context, req *mcp.CallToolRequest, input diskUsageInput) (*mcp.CallToolResult, *diskUsageOutput, error) {
+	if input.TopN < 0 || input.Workers < 0 {
+		return nil, nil, fmt.Errorf("top_n and workers must not be negative")
+	}
+	topN := input.TopN
+	if topN == 0 {
+		topN = defaultDiskUsageTopN
+	}
+	topN = min(topN, maxDiskUsageTopN)
+	workers := input.Workers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+
+	path := input.Path
+	if path == "" {
+		path = "~"
+	}
+	root, err := expandHome(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve %s: %w", path, err)
+	}
+
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read directory %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return nil, nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	rules, err := parseIgnorePatterns(input.Exclude, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scan := &diskUsageScan{
+		ctx:         ctx,
+		input:       input,
+		topN:        topN,
+		sem:         make(chan struct{}, workers),
+		out:         &diskUsageOutput{Path: root},
+		files:       []diskUsageFile{},
+		byExt:       make(map[string]*diskUsageBucket),
+		byCategory:  make(map[string]*diskUsageBucket),
+		seenFiles:   make(map[[2]uint64]bool),
+		visitedDirs: make(map[string]bool),
+	}
+	scan.rootDev, _, scan.hasDev = fileID(info)
+	scan.firstVisit(root, info)
+
+	start := time.Now()
+	scan.walk(root, "", ignoreMatcher(rules))
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	sort.Slice(scan.dirs, func(i, j int) bool { return scan.dirs[i].Size > scan.dirs[j].Size })
+	if len(scan.dirs) > topN {
+		scan.dirs = scan.dirs[:topN]
+	}
+
+	output := scan.out
+	output.LargestDirectories = append([]diskUsageDir{}, scan.dirs...)
+	output.LargestFiles = scan.files
+	output.ByExtension = topBuckets(scan.byExt, topN)
+	output.ByCategory = topBuckets(scan.byCategory, 0)
+	output.Elapsed = time.Since(start).Round(time.Millisecond).String()
+
+	return nil, output, nil
+}
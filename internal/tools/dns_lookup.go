@@ -0,0 +1,478 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// resolvConfPath is the resolver configuration file read on Unix systems
+var resolvConfPath = "/etc/resolv.conf"
+
+const defaultDNSTimeout = 5 * time.Second
+
+type dnsLookupInput struct {
+	Name           string   `json:"name" jsonschema:"Host name to resolve, or an IP address for a reverse (PTR) lookup. SRV lookups expect the full name, e.g. '_http._tcp.example.com'"`
+	Types          []string `json:"types,omitempty" jsonschema:"Record types to query (A, AAAA, CNAME, MX, TXT, SRV, PTR). Defaults to A, AAAA, CNAME, MX and TXT for names and PTR for IP addresses"`
+	Server         string   `json:"server,omitempty" jsonschema:"DNS server to query instead of the system resolver, e.g. '127.0.0.1:5353' or '1.1.1.1' (port 53 is assumed). The server is asked directly: the hosts file and search domains are not applied"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty" jsonschema:"Timeout for all queries in seconds, defaults to 5"`
+}
+
+type mxRecord struct {
+	Host string `json:"host" jsonschema:"Mail server host name"`
+	Pref uint16 `json:"pref" jsonschema:"Preference, lower is preferred"`
+}
+
+type srvRecord struct {
+	Target   string `json:"target" jsonschema:"Target host name"`
+	Port     uint16 `json:"port" jsonschema:"Target port"`
+	Priority uint16 `json:"priority" jsonschema:"Priority, lower is preferred"`
+	Weight   uint16 `json:"weight" jsonschema:"Relative weight among records with the same priority"`
+}
+
+type dnsRecords struct {
+	A     []string    `json:"a,omitempty" jsonschema:"IPv4 addresses"`
+	AAAA  []string    `json:"aaaa,omitempty" jsonschema:"IPv6 addresses"`
+	CNAME string      `json:"cname,omitempty" jsonschema:"Canonical name"`
+	MX    []mxRecord  `json:"mx,omitempty" jsonschema:"Mail exchange records"`
+	TXT   []string    `json:"txt,omitempty" jsonschema:"Text records"`
+	SRV   []srvRecord `json:"srv,omitempty" jsonschema:"Service records"`
+	PTR   []string    `json:"ptr,omitempty" jsonschema:"Reverse lookup names"`
+}
+
+type hostsEntry struct {
+	Line  int      `json:"line" jsonschema:"Line number in the hosts file"`
+	IP    string   `json:"ip" jsonschema:"IP address of the entry"`
+	Names []string `json:"names" jsonschema:"Host names mapped to the IP address"`
+}
+
+type resolvConf struct {
+	Nameservers []string `json:"nameservers" jsonschema:"Configured nameservers"`
+	Search      []string `json:"search" jsonschema:"Search domains"`
+	Options     []string `json:"options,omitempty" jsonschema:"Resolver options"`
+}
+
+type dnsLookupOutput struct {
+	Name         string            `json:"name" jsonschema:"Name that was looked up"`
+	Server       string            `json:"server" jsonschema:"DNS server that was queried, or 'system' for the system resolver"`
+	Records      dnsRecords        `json:"records" jsonschema:"Records found, grouped by type"`
+	Errors       map[string]string `json:"errors,omitempty" jsonschema:"Lookup errors by record type"`
+	HostsFile    string            `json:"hosts_file" jsonschema:"Path of the hosts file that was inspected"`
+	HostsEntries []hostsEntry      `json:"hosts_entries" jsonschema:"Hosts file entries matching the name"`
+	ResolvConf   *resolvConf       `json:"resolv_conf,omitempty" jsonschema:"Nameservers and search domains from resolv.conf"`
+}
+
+// defaultHostsPath returns the location of the system hosts file
+func defaultHostsPath() string {
+	if runtime.GOOS == "windows" {
+		root := os.Getenv("SystemRoot")
+		if root == "" {
+			root = `C:\Windows`
+		}
+		return filepath.Join(root, "System32", "drivers", "etc", "hosts")
+	}
+	return "/etc/hosts"
+}
+
+// parseHostsFile reads the IP to host name mappings of a hosts file
+func parseHostsFile(path string) ([]hostsEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []hostsEntry{}
+	scanner := bufio.NewScanner(f)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(text)
+		if len(fields) < 2 || net.ParseIP(fields[0]) == nil {
+			continue
+		}
+
+		entries = append(entries, hostsEntry{
+			Line:  line,
+			IP:    fields[0],
+			Names: fields[1:],
+		})
+	}
+
+	return entries, scanner.Err()
+}
+
+// parseResolvConf reads nameservers, search domains and options from a resolv.conf file
+func parseResolvConf(path string) (*resolvConf, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	conf := &resolvConf{
+		Nameservers: []string{},
+		Search:      []string{},
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+
+		switch fields[0] {
+		case "nameserver":
+			conf.Nameservers = append(conf.Nameservers, fields[1])
+		case "search", "domain":
+			// The last search or domain line wins
+			conf.Search = fields[1:]
+		case "options":
+			conf.Options = append(conf.Options, fields[1:]...)
+		}
+	}
+
+	return conf, nil
+}
+
+// parseDNSServer validates a DNS server address, adding port 53 when it has none
+func parseDNSServer(server string) (string, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(strings.Trim(server, "[]"), "53")
+	}
+
+	host, _, _ := net.SplitHostPort(server)
+	if net.ParseIP(host) == nil {
+		return "", fmt.Errorf("invalid DNS server address '%s', expected an IP address with optional port", server)
+	}
+
+	return server, nil
+}
+
+// exchangeDNS sends a single query to a DNS server over UDP, retrying over TCP
+// when the answer is truncated. Unlike the Go resolver it never consults the
+// hosts file, so the answer is always the server's own.
+func exchangeDNS(ctx context.Context, server string, name string, qtype dnsmessage.Type) ([]dnsmessage.Resource, error) {
+	qname, err := dnsmessage.NewName(strings.TrimSuffix(name, ".") + ".")
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s: %w", name, err)
+	}
+
+	id := uint16(rand.Uint32())
+	query, err := (&dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}},
+	}).Pack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var resp dnsmessage.Message
+	for _, network := range []string{"udp", "tcp"} {
+		if resp, err = exchangeDNSOver(ctx, network, server, query, id); err != nil {
+			return nil, err
+		}
+		if !resp.Truncated {
+			break
+		}
+	}
+
+	switch resp.RCode {
+	case dnsmessage.RCodeSuccess:
+	case dnsmessage.RCodeNameError:
+		return nil, fmt.Errorf("no such host %s on %s", name, server)
+	default:
+		return nil, fmt.Errorf("server %s answered %s", server, resp.RCode)
+	}
+
+	return resp.Answers, nil
+}
+
+// exchangeDNSOver sends a packed query over one network and reads the matching response
+func exchangeDNSOver(ctx context.Context, network, server string, query []byte, id uint16) (dnsmessage.Message, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, server)
+	if err != nil {
+		return dnsmessage.Message{}, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	var resp dnsmessage.Message
+	if network == "tcp" {
+		// DNS over TCP prefixes each message with its length
+		if _, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(query))), query...)); err != nil {
+			return resp, err
+		}
+		var length [2]byte
+		if _, err := io.ReadFull(conn, length[:]); err != nil {
+			return resp, err
+		}
+		buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return resp, err
+		}
+		if err := resp.Unpack(buf); err != nil {
+			return resp, fmt.Errorf("invalid response from %s: %w", server, err)
+		}
+		if resp.ID != id {
+			return resp, fmt.Errorf("response from %s does not match the query", server)
+		}
+		return resp, nil
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return resp, err
+	}
+	buf := make([]byte, 65535)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return resp, err
+		}
+		// Skip stray datagrams, such as late answers to an earlier query
+		if err := resp.Unpack(buf[:n]); err != nil || resp.ID != id || !resp.Response {
+			continue
+		}
+		return resp, nil
+	}
+}
+
+// reverseName returns the in-addr.arpa or ip6.arpa name of an IP address
+func reverseName(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa.", v4[3], v4[2], v4[1], v4[0])
+	}
+
+	var b strings.Builder
+	for i := len(ip) - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "%x.%x.", ip[i]&0x0f, ip[i]>>4)
+	}
+	b.WriteString("ip6.arpa.")
+	return b.String()
+}
+
+// lookupRecordsFrom runs a single record type query against a DNS server and
+// stores its answer in records
+func lookupRecordsFrom(ctx context.Context, server, name, recordType string, records *dnsRecords) error {
+	qtypes := map[string]dnsmessage.Type{
+		"A": dnsmessage.TypeA, "AAAA": dnsmessage.TypeAAAA, "CNAME": dnsmessage.TypeCNAME,
+		"MX": dnsmessage.TypeMX, "TXT": dnsmessage.TypeTXT, "SRV": dnsmessage.TypeSRV, "PTR": dnsmessage.TypePTR,
+	}
+	qtype, ok := qtypes[recordType]
+	if !ok {
+		return fmt.Errorf("unsupported record type")
+	}
+
+	qname := name
+	if recordType == "PTR" {
+		ip := net.ParseIP(name)
+		if ip == nil {
+			return fmt.Errorf("PTR lookups need an IP address")
+		}
+		qname = reverseName(ip)
+	}
+
+	answers, err := exchangeDNS(ctx, server, qname, qtype)
+	if err != nil {
+		return err
+	}
+
+	// Answers may include the CNAME chain leading to the requested records
+	found := false
+	for _, rr := range answers {
+		switch body := rr.Body.(type) {
+		case *dnsmessage.AResource:
+			if recordType == "A" {
+				records.A = append(records.A, net.IP(body.A[:]).String())
+				found = true
+			}
+		case *dnsmessage.AAAAResource:
+			if recordType == "AAAA" {
+				records.AAAA = append(records.AAAA, net.IP(body.AAAA[:]).String())
+				found = true
+			}
+		case *dnsmessage.CNAMEResource:
+			if recordType == "CNAME" {
+				records.CNAME = body.CNAME.String()
+				found = true
+			}
+		case *dnsmessage.MXResource:
+			records.MX = append(records.MX, mxRecord{Host: body.MX.String(), Pref: body.Pref})
+			found = true
+		case *dnsmessage.TXTResource:
+			records.TXT = append(records.TXT, strings.Join(body.TXT, ""))
+			found = true
+		case *dnsmessage.SRVResource:
+			records.SRV = append(records.SRV, srvRecord{Target: body.Target.String(), Port: body.Port, Priority: body.Priority, Weight: body.Weight})
+			found = true
+		case *dnsmessage.PTRResource:
+			records.PTR = append(records.PTR, body.PTR.String())
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("no %s records for %s on %s", recordType, name, server)
+	}
+
+	return nil
+}
+
+// lookupRecords runs a single record type query and stores its answer in records
+func lookupRecords(ctx context.Context, r *net.Resolver, name, recordType string, records *dnsRecords) error {
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := r.LookupIP(ctx, network, name)
+		if err != nil {
+			return err
+		}
+		addrs := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			addrs = append(addrs, ip.String())
+		}
+		if recordType == "A" {
+			records.A = addrs
+		} else {
+			records.AAAA = addrs
+		}
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, name)
+		if err != nil {
+			return err
+		}
+		records.CNAME = cname
+	case "MX":
+		mxs, err := r.LookupMX(ctx, name)
+		if err != nil {
+			return err
+		}
+		for _, mx := range mxs {
+			records.MX = append(records.MX, mxRecord{Host: mx.Host, Pref: mx.Pref})
+		}
+	case "TXT":
+		txts, err := r.LookupTXT(ctx, name)
+		if err != nil {
+			return err
+		}
+		records.TXT = txts
+	case "SRV":
+		_, srvs, err := r.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return err
+		}
+		for _, srv := range srvs {
+			records.SRV = append(records.SRV, srvRecord{Target: srv.Target, Port: srv.Port, Priority: srv.Priority, Weight: srv.Weight})
+		}
+	case "PTR":
+		names, err := r.LookupAddr(ctx, name)
+		if err != nil {
+			return err
+		}
+		records.PTR = names
+	default:
+		return fmt.Errorf("unsupported record type")
+	}
+
+	return nil
+}
+
+// DNSLookup resolves a name and reports matching hosts file entries and the resolver configuration
+func DNSLookup(ctx context.Context, req *mcp.CallToolRequest, input dnsLookupInput) (*mcp.CallToolResult, *dnsLookupOutput, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, nil, fmt.Errorf("name is required")
+	}
+
+	isIP := net.ParseIP(name) != nil
+
+	types := input.Types
+	if len(types) == 0 {
+		types = []string{"A", "AAAA", "CNAME", "MX", "TXT"}
+		if isIP {
+			types = []string{"PTR"}
+		}
+	}
+
+	server := "system"
+	if input.Server != "" {
+		var err error
+		if server, err = parseDNSServer(input.Server); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	timeout := defaultDNSTimeout
+	if input.TimeoutSeconds > 0 {
+		timeout = time.Duration(input.TimeoutSeconds) * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output := &dnsLookupOutput{
+		Name:         name,
+		Server:       server,
+		HostsFile:    defaultHostsPath(),
+		HostsEntries: []hostsEntry{},
+	}
+
+	for _, t := range types {
+		t = strings.ToUpper(strings.TrimSpace(t))
+		var err error
+		if input.Server != "" {
+			err = lookupRecordsFrom(ctx, server, name, t, &output.Records)
+		} else {
+			err = lookupRecords(ctx, net.DefaultResolver, name, t, &output.Records)
+		}
+		if err != nil {
+			if output.Errors == nil {
+				output.Errors = make(map[string]string)
+			}
+			output.Errors[t] = err.Error()
+		}
+	}
+
+	if entries, err := parseHostsFile(output.HostsFile); err == nil {
+		for _, entry := range entries {
+			if isIP && net.ParseIP(entry.IP).Equal(net.ParseIP(name)) {
+				output.HostsEntries = append(output.HostsEntries, entry)
+				continue
+			}
+			for _, n := range entry.Names {
+				if strings.EqualFold(strings.TrimSuffix(n, "."), strings.TrimSuffix(name, ".")) {
+					output.HostsEntries = append(output.HostsEntries, entry)
+					break
+				}
+			}
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		if conf, err := parseResolvConf(resolvConfPath); err == nil {
+			output.ResolvConf = conf
+		}
+	}
+
+	return nil, output, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux && !openbsd && !darwin && !freebsd && !netbsd && !windows
+
+package tools
+
+import (
+	"os"
+	"time"
+)
+
+// fileAccessTime reports that access times are not available on this OS
+func fileAccessTime(info os.FileInfo) (time.Time, bool) {
+	return time.Time{}, false
+}
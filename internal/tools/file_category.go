@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// extensionCategories maps lower-case file extensions to a broad category
+var extensionCategories = invertCategories(map[string][]string{
+	"images":     {".jpg", ".jpeg", ".png", ".gif", ".bmp", ".webp", ".svg", ".ico", ".tif", ".tiff", ".heic", ".heif", ".avif", ".raw", ".cr2", ".nef", ".psd"},
+	"videos":     {".mp4", ".mkv", ".mov", ".avi", ".wmv", ".webm", ".flv", ".m4v", ".mpg", ".mpeg", ".3gp"},
+	"audio":      {".mp3", ".wav", ".flac", ".aac", ".ogg", ".oga", ".m4a", ".wma", ".opus", ".aiff", ".mid", ".midi"},
+	"documents":  {".pdf", ".doc", ".docx", ".xls", ".xlsx", ".ppt", ".pptx", ".odt", ".ods", ".odp", ".rtf", ".txt", ".md", ".csv", ".epub", ".pages", ".numbers", ".key", ".tex"},
+	"archives":   {".zip", ".tar", ".gz", ".tgz", ".bz2", ".xz", ".zst", ".7z", ".rar", ".lz", ".lzma", ".cab", ".iso"},
+	"installers": {".dmg", ".pkg", ".exe", ".msi", ".deb", ".rpm", ".apk", ".appimage", ".flatpak", ".snap", ".msix"},
+	"code":       {".go", ".js", ".mjs", ".ts", ".tsx", ".jsx", ".py", ".rb", ".rs", ".c", ".h", ".cpp", ".hpp", ".java", ".kt", ".swift", ".cs", ".php", ".sh", ".ps1", ".html", ".css", ".json", ".yaml", ".yml", ".toml", ".xml", ".sql", ".ipynb"},
+	"fonts":      {".ttf", ".otf", ".woff", ".woff2"},
+})
+
+// invertCategories turns category → extensions into extension → category
+func invertCategories(categories map[string][]string) map[string]string {
+	m := make(map[string]string)
+	for category, exts := range categories {
+		for _, ext := range exts {
+			m[ext] = category
+		}
+	}
+	return m
+}
+
+// fileExtension returns the lower-case extension of name, treating ".tar.gz" style
+// double extensions as one
+func fileExtension(name string) string {
+	lower := strings.ToLower(name)
+	for _, double := range []string{".tar.gz", ".tar.bz2", ".tar.xz", ".tar.zst"} {
+		if strings.HasSuffix(lower, double) && len(lower) > len(double) {
+			return double
+		}
+	}
+	return filepath.Ext(lower)
+}
+
+// categoryForExtension returns the category of a file extension, falling back to
+// the top-level MIME type for extensions not in the table
+func categoryForExtension(ext string) string {
+	ext = strings.ToLower(ext)
+	if strings.HasPrefix(ext, ".tar.") {
+		return "archives"
+	}
+	if category, ok := extensionCategories[ext]; ok {
+		return category
+	}
+	if ext != "" {
+		if mt := mime.TypeByExtension(ext); mt != "" {
+			return categoryForMIME(mt)
+		}
+	}
+	return "other"
+}
+
+// categoryForMIME returns the category of a MIME type such as "image/png"
+func categoryForMIME(mt string) string {
+	mt, _, _ = strings.Cut(mt, ";")
+	switch top, sub, _ := strings.Cut(strings.TrimSpace(mt), "/"); {
+	case top == "image":
+		return "images"
+	case top == "video":
+		return "videos"
+	case top == "audio":
+		return "audio"
+	case top == "font":
+		return "fonts"
+	case sub == "pdf" || sub == "rtf" || mt == "text/plain" || mt == "text/csv":
+		return "documents"
+	case sub == "zip" || sub == "gzip" || sub == "x-gzip" || sub == "x-tar" || sub == "x-7z-compressed" || sub == "x-rar-compressed" || sub == "vnd.rar" || sub == "x-bzip2" || sub == "x-xz":
+		return "archives"
+	case sub == "x-msdownload" || sub == "x-apple-diskimage" || sub == "vnd.debian.binary-package" || sub == "x-rpm":
+		return "installers"
+	case sub == "javascript" || sub == "json" || sub == "xml" || sub == "wasm" || sub == "html" || sub == "css" || strings.HasPrefix(sub, "x-script") || strings.HasSuffix(sub, "src"):
+		return "code"
+	case top == "text":
+		return "documents"
+	}
+	return "other"
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !openbsd && !darwin && !freebsd && !netbsd
+
+package tools
+
+import "os"
+
+// fileID reports that device and inode numbers are not available on this OS
+func fileID(info os.FileInfo) (dev, ino uint64, ok bool) {
+	return 0, 0, false
+}
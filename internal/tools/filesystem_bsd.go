@@ -0,0 +1,74 @@
+//go:build darwin || freebsd
+
+package tools
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// int8String converts a NUL-terminated C char array to a string
+func int8String(chars []int8) string {
+	b := make([]byte, 0, len(chars))
+	for _, c := range chars {
+		if c == 0 {
+			break
+		}
+		b = append(b, byte(c))
+	}
+	return string(b)
+}
+
+// nonNegative clamps counts that some systems report as negative to zero
+func nonNegative(v int64) uint64 {
+	if v < 0 {
+		return 0
+	}
+	return uint64(v)
+}
+
+// statFilesystem returns the capacity of the filesystem holding path
+func statFilesystem(path string) (fsCapacity, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return fsCapacity{}, err
+	}
+
+	bsize := uint64(st.Bsize)
+	return fsCapacity{
+		Total:      uint64(st.Blocks) * bsize,
+		Free:       uint64(st.Bfree) * bsize,
+		Available:  nonNegative(int64(st.Bavail)) * bsize,
+		Inodes:     uint64(st.Files),
+		InodesFree: nonNegative(int64(st.Ffree)),
+		HasInodes:  st.Files > 0,
+	}, nil
+}
+
+// listMounts returns the mounted filesystems reported by getfsstat
+func listMounts() ([]mountInfo, error) {
+	n, err := syscall.Getfsstat(nil, 1 /* MNT_WAIT */)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mount table: %w", err)
+	}
+	buf := make([]syscall.Statfs_t, n)
+	if n, err = syscall.Getfsstat(buf, 1); err != nil {
+		return nil, fmt.Errorf("failed to read mount table: %w", err)
+	}
+
+	mounts := make([]mountInfo, 0, n)
+	for _, st := range buf[:n] {
+		m := mountInfo{
+			MountPoint: int8String(st.Mntonname[:]),
+			FSType:     int8String(st.Fstypename[:]),
+			Source:     int8String(st.Mntfromname[:]),
+			Root:       "/",
+			Options:    "rw",
+		}
+		if st.Flags&1 != 0 { // MNT_RDONLY
+			m.Options = "ro"
+		}
+		mounts = append(mounts, m)
+	}
+	return mounts, nil
+}
@@ -0,0 +1,32 @@
+//go:build linux
+
+package tools
+
+import "syscall"
+
+// statFilesystem returns the capacity of the filesystem holding path
+func statFilesystem(path string) (fsCapacity, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return fsCapacity{}, err
+	}
+
+	bsize := uint64(st.Frsize)
+	if bsize == 0 {
+		bsize = uint64(st.Bsize)
+	}
+
+	return fsCapacity{
+		Total:      uint64(st.Blocks) * bsize,
+		Free:       uint64(st.Bfree) * bsize,
+		Available:  uint64(st.Bavail) * bsize,
+		Inodes:     uint64(st.Files),
+		InodesFree: uint64(st.Ffree),
+		HasInodes:  st.Files > 0,
+	}, nil
+}
+
+// listMounts returns the mounted filesystems from /proc/self/mountinfo
+func listMounts() ([]mountInfo, error) {
+	return readMountInfo(procRoot)
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package tools
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// statFilesystem reports that filesystem capacity is not available on this OS
+func statFilesystem(path string) (fsCapacity, error) {
+	return fsCapacity{}, fmt.Errorf("filesystem capacity is not supported on %s", runtime.GOOS)
+}
+
+// listMounts reports that the mount table is not available on this OS
+func listMounts() ([]mountInfo, error) {
+	return nil, fmt.Errorf("listing filesystems is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,58 @@
+//go:build windows
+
+package tools
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                 = syscall.NewLazyDLL("kernel32.dll")
+	procGetDiskFreeSpaceExW  = kernel32.NewProc("GetDiskFreeSpaceExW")
+	procGetLogicalDrives     = kernel32.NewProc("GetLogicalDrives")
+	procGetVolumeInformation = kernel32.NewProc("GetVolumeInformationW")
+)
+
+// statFilesystem returns the capacity of the volume holding path
+func statFilesystem(path string) (fsCapacity, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return fsCapacity{}, err
+	}
+
+	var available, total, free uint64
+	ok, _, err := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&available)), uintptr(unsafe.Pointer(&total)), uintptr(unsafe.Pointer(&free)))
+	if ok == 0 {
+		return fsCapacity{}, err
+	}
+
+	return fsCapacity{Total: total, Free: free, Available: available}, nil
+}
+
+// listMounts returns one entry per drive letter
+func listMounts() ([]mountInfo, error) {
+	mask, _, err := procGetLogicalDrives.Call()
+	if mask == 0 {
+		return nil, fmt.Errorf("failed to list drives: %w", err)
+	}
+
+	var mounts []mountInfo
+	for i := 0; i < 26; i++ {
+		if mask&(1<<i) == 0 {
+			continue
+		}
+		root := string(rune('A'+i)) + `:\`
+		m := mountInfo{MountPoint: root, Source: root[:2], Root: `\`}
+
+		rootPtr, _ := syscall.UTF16PtrFromString(root)
+		fsName := make([]uint16, syscall.MAX_PATH+1)
+		if ok, _, _ := procGetVolumeInformation.Call(uintptr(unsafe.Pointer(rootPtr)), 0, 0, 0, 0, 0, uintptr(unsafe.Pointer(&fsName[0])), uintptr(len(fsName))); ok != 0 {
+			m.FSType = syscall.UTF16ToString(fsName)
+		}
+
+		mounts = append(mounts, m)
+	}
+	return mounts, nil
+}
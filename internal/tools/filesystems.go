@@ -0,0 +1,256 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultUsageThreshold = 90
+	// statfsTimeout keeps an unreachable network mount from blocking the whole report
+	statfsTimeout = 2 * time.Second
+)
+
+// pseudoFilesystems are kernel filesystems that hold no user data
+var pseudoFilesystems = map[string]bool{
+	"proc": true, "sysfs": true, "cgroup": true, "cgroup2": true, "devpts": true, "mqueue": true,
+	"debugfs": true, "tracefs": true, "securityfs": true, "pstore": true, "bpf": true, "configfs": true,
+	"fusectl": true, "hugetlbfs": true, "autofs": true, "binfmt_misc": true, "nsfs": true,
+	"rpc_pipefs": true, "selinuxfs": true, "efivarfs": true, "devfs": true, "nullfs": true,
+}
+
+// fsCapacity is the space and inode usage reported by statfs
+type fsCapacity struct {
+	Total, Free, Available uint64
+	Inodes, InodesFree     uint64
+	HasInodes              bool
+}
+
+type listFilesystemsInput struct {
+	Path      string   `json:"path,omitempty" jsonschema:"Only report the filesystem this path is on, '~' is expanded"`
+	Threshold float64  `json:"threshold,omitempty" jsonschema:"Usage percentage above which a filesystem is flagged, defaults to 90"`
+	Types     []string `json:"types,omitempty" jsonschema:"Only list these filesystem types, e.g. ['ext4', 'btrfs']"`
+	All       bool     `json:"all,omitempty" jsonschema:"Include pseudo filesystems (proc, sysfs, cgroup...), empty filesystems, repeated bind mounts and mounts hidden by a later mount on the same directory"`
+}
+
+type filesystemEntry struct {
+	MountPoint        string  `json:"mount_point" jsonschema:"Where the filesystem is mounted"`
+	Source            string  `json:"source" jsonschema:"Device or source of the mount, e.g. /dev/sda1"`
+	Type              string  `json:"type" jsonschema:"Filesystem type"`
+	Device            string  `json:"device,omitempty" jsonschema:"Major:minor device number"`
+	Root              string  `json:"root,omitempty" jsonschema:"Directory of the filesystem mounted here, '/' unless it is a bind mount"`
+	Options           string  `json:"options,omitempty" jsonschema:"Mount options"`
+	ReadOnly          bool    `json:"read_only" jsonschema:"Whether the filesystem is mounted read-only"`
+	Total             uint64  `json:"total" jsonschema:"Size in bytes"`
+	Used              uint64  `json:"used" jsonschema:"Used bytes"`
+	Free              uint64  `json:"free" jsonschema:"Free bytes, including space reserved for root"`
+	Available         uint64  `json:"available" jsonschema:"Bytes available to unprivileged users"`
+	UsedPercent       float64 `json:"used_percent" jsonschema:"Used space as a percentage of the space usable by unprivileged users, as df reports it"`
+	InodesTotal       uint64  `json:"inodes_total,omitempty" jsonschema:"Number of inodes"`
+	InodesUsed        uint64  `json:"inodes_used,omitempty" jsonschema:"Used inodes"`
+	InodesFree        uint64  `json:"inodes_free,omitempty" jsonschema:"Free inodes"`
+	InodesUsedPercent float64 `json:"inodes_used_percent,omitempty" jsonschema:"Used inodes as a percentage"`
+	AboveThreshold    bool    `json:"above_threshold" jsonschema:"Whether space or inode usage is above the threshold"`
+	Error             string  `json:"error,omitempty" jsonschema:"Why the capacity could not be read"`
+}
+
+type listFilesystemsOutput struct {
+	System         string            `json:"system" jsonschema:"Operating system of the server"`
+	Threshold      float64           `json:"threshold" jsonschema:"Usage percentage used to flag filesystems"`
+	Path           string            `json:"path,omitempty" jsonschema:"Resolved path that was looked up"`
+	Filesystems    []filesystemEntry `json:"filesystems" jsonschema:"Mounted filesystems"`
+	AboveThreshold []string          `json:"above_threshold" jsonschema:"Mount points whose space or inode usage is above the threshold"`
+}
+
+// statWithTimeout runs statFilesystem, giving up after statfsTimeout
+func statWithTimeout(ctx context.Context, path string) (fsCapacity, error) {
+	type result struct {
+		capacity fsCapacity
+		err      error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		capacity, err := statFilesystem(path)
+		ch <- result{capacity, err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.capacity, r.err
+	case <-time.After(statfsTimeout):
+		return fsCapacity{}, fmt.Errorf("timed out after %s, the filesystem may be unreachable", statfsTimeout)
+	case <-ctx.Done():
+		return fsCapacity{}, ctx.Err()
+	}
+}
+
+// percent returns part/total as a percentage rounded to one decimal
+func percent(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*1000) / 10
+}
+
+// findMount returns the mount holding path
+func findMount(mounts []mountInfo, path string) (mountInfo, bool) {
+	if runtime.GOOS == "windows" {
+		volume := filepath.VolumeName(path) + `\`
+		for _, m := range mounts {
+			if strings.EqualFold(m.MountPoint, volume) {
+				return m, true
+			}
+		}
+		return mountInfo{}, false
+	}
+	return mountForPath(mounts, path)
+}
+
+// hasMountOption reports whether a comma-separated option list contains opt
+func hasMountOption(options, opt string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// ListFilesystems lists mounted filesystems with their space and inode usage
+func ListFilesystems(ctx context.Context, req *mcp.CallToolRequest, input listFilesystemsInput) (*mcp.CallToolResult, *listFilesystemsOutput, error) {
+	threshold := input.Threshold
+	if threshold == 0 {
+		threshold = defaultUsageThreshold
+	}
+	if threshold < 0 || threshold > 100 {
+		return nil, nil, fmt.Errorf("threshold must be between 0 and 100, got %g", threshold)
+	}
+
+	mounts, err := listMounts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &listFilesystemsOutput{
+		System:         runtime.GOOS,
+		Threshold:      threshold,
+		Filesystems:    []filesystemEntry{},
+		AboveThreshold: []string{},
+	}
+
+	if input.Path != "" {
+		path, err := expandHome(input.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if path, err = filepath.Abs(path); err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve %s: %w", input.Path, err)
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		} else {
+			return nil, nil, fmt.Errorf("failed to resolve %s: %w", input.Path, err)
+		}
+		output.Path = path
+
+		m, ok := findMount(mounts, path)
+		if !ok {
+			return nil, nil, fmt.Errorf("no mounted filesystem found for %s", path)
+		}
+		mounts = []mountInfo{m}
+	}
+
+	types := make(map[string]bool)
+	for _, t := range input.Types {
+		types[strings.ToLower(t)] = true
+	}
+
+	// A filesystem mounted over another hides it; statfs only sees the last one
+	lastMount := make(map[string]int)
+	for i, m := range mounts {
+		lastMount[m.MountPoint] = i
+	}
+
+	seenDevices := make(map[string]bool)
+	for i, m := range mounts {
+		if !input.All && lastMount[m.MountPoint] != i {
+			continue
+		}
+		if len(types) > 0 && !types[strings.ToLower(m.FSType)] {
+			continue
+		}
+		if !input.All && input.Path == "" && pseudoFilesystems[m.FSType] {
+			continue
+		}
+
+		entry := filesystemEntry{
+			MountPoint: m.MountPoint,
+			Source:     m.Source,
+			Type:       m.FSType,
+			Root:       m.Root,
+			Options:    m.Options,
+			ReadOnly:   hasMountOption(m.Options, "ro"),
+		}
+		if m.Major != 0 || m.Minor != 0 {
+			entry.Device = fmt.Sprintf("%d:%d", m.Major, m.Minor)
+		}
+
+		// Bind mounts of the same directory of a device repeat the same numbers
+		if !input.All && input.Path == "" && entry.Device != "" {
+			key := entry.Device + " " + m.Root
+			if seenDevices[key] {
+				continue
+			}
+			seenDevices[key] = true
+		}
+
+		capacity, err := statWithTimeout(ctx, m.MountPoint)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			entry.Error = err.Error()
+			output.Filesystems = append(output.Filesystems, entry)
+			continue
+		}
+		if !input.All && input.Path == "" && capacity.Total == 0 {
+			continue
+		}
+
+		entry.Total = capacity.Total
+		entry.Free = capacity.Free
+		entry.Available = capacity.Available
+		if capacity.Total > capacity.Free {
+			entry.Used = capacity.Total - capacity.Free
+		}
+		entry.UsedPercent = percent(entry.Used, entry.Used+entry.Available)
+
+		if capacity.HasInodes {
+			entry.InodesTotal = capacity.Inodes
+			entry.InodesFree = capacity.InodesFree
+			if capacity.Inodes > capacity.InodesFree {
+				entry.InodesUsed = capacity.Inodes - capacity.InodesFree
+			}
+			entry.InodesUsedPercent = percent(entry.InodesUsed, entry.InodesTotal)
+		}
+
+		if entry.UsedPercent >= threshold || entry.InodesUsedPercent >= threshold {
+			entry.AboveThreshold = true
+			output.AboveThreshold = append(output.AboveThreshold, entry.MountPoint)
+		}
+
+		output.Filesystems = append(output.Filesystems, entry)
+	}
+
+	sort.SliceStable(output.Filesystems, func(i, j int) bool { return output.Filesystems[i].MountPoint < output.Filesystems[j].MountPoint })
+
+	return nil, output, nil
+}
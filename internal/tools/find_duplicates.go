@@ -0,0 +1,374 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// duplicatePartialSize is how much of the start and end of a file the partial hash reads
+	duplicatePartialSize  = 4096
+	defaultDuplicateLimit = 50
+	maxDuplicateWorkers   = 16
+)
+
+// copyMarker matches names browsers and file managers give to copies, such as
+// "report (1)", "report copy", "report - Copy (2)" or "report(3)"
+var copyMarker = regexp.MustCompile(`(?i)(\s*\(\d+\)|\s+-?\s*copy(\s*\(?\d+\)?)?)$`)
+
+type findDuplicatesInput struct {
+	Directories []string `json:"directories,omitempty" jsonschema:"Directories to scan, '~' is expanded. Defaults to the Downloads directory"`
+	Recursive   bool     `json:"recursive,omitempty" jsonschema:"Scan subdirectories"`
+	Include     []string `json:"include,omitempty" jsonschema:"Glob patterns (e.g. '*.pdf') a file name or relative path must match"`
+	Exclude     []string `json:"exclude,omitempty" jsonschema:"Glob patterns of file names or relative paths to skip; matching directories are not scanned"`
+	MinSize     int64    `json:"min_size,omitempty" jsonschema:"Minimum file size in bytes, defaults to 1 so empty files are ignored"`
+	Workers     int      `json:"workers,omitempty" jsonschema:"Number of files hashed concurrently, defaults to the number of CPUs (at most 16)"`
+	Limit       int      `json:"limit,omitempty" jsonschema:"Maximum number of groups to return, largest waste first. Defaults to 50"`
+}
+
+type duplicateFile struct {
+	Path           string    `json:"path" jsonschema:"Full path of the file"`
+	LastModifyTime time.Time `json:"last_modify" jsonschema:"Last modify time of the file"`
+}
+
+type duplicateGroup struct {
+	SHA256      string          `json:"sha256" jsonschema:"SHA-256 of the file contents"`
+	Size        int64           `json:"size" jsonschema:"Size of each file in bytes"`
+	Files       []duplicateFile `json:"files" jsonschema:"Files with identical contents"`
+	Keeper      string          `json:"keeper" jsonschema:"Suggested file to keep: not named like a copy, then the oldest, then the shortest path"`
+	WastedBytes int64           `json:"wasted_bytes" jsonschema:"Bytes freed by removing all files but the keeper"`
+}
+
+type findDuplicatesOutput struct {
+	Directories    []string         `json:"directories" jsonschema:"Directories that were scanned"`
+	FilesScanned   int              `json:"files_scanned" jsonschema:"Number of files considered"`
+	FilesHashed    int              `json:"files_hashed" jsonschema:"Number of files whose full contents were hashed"`
+	HardLinks      int              `json:"hard_links" jsonschema:"Files skipped because they are hard links to a file already scanned; removing them frees no space"`
+	TotalGroups    int              `json:"total_groups" jsonschema:"Number of duplicate groups found"`
+	DuplicateFiles int              `json:"duplicate_files" jsonschema:"Number of redundant copies across all groups"`
+	WastedBytes    int64            `json:"wasted_bytes" jsonschema:"Bytes freed by keeping one file per group"`
+	HasMore        bool             `json:"has_more" jsonschema:"Whether more groups were found than returned"`
+	Groups         []duplicateGroup `json:"groups" jsonschema:"Duplicate groups, largest waste first"`
+	Errors         []string         `json:"errors,omitempty" jsonschema:"Paths that could not be read"`
+}
+
+type duplicateCandidate struct {
+	path    string
+	size    int64
+	modTime time.Time
+	info    os.FileInfo
+}
+
+// hashFileContents returns the SHA-256 of a file, or of its first and last
+// duplicatePartialSize bytes when partial is set
+func hashFileContents(ctx context.Context, path string, size int64, partial bool) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if partial && size > 2*duplicatePartialSize {
+		if _, err := io.CopyN(h, f, duplicatePartialSize); err != nil {
+			return "", err
+		}
+		if _, err := f.Seek(-duplicatePartialSize, io.SeekEnd); err != nil {
+			return "", err
+		}
+		if _, err := io.CopyN(h, f, duplicatePartialSize); err != nil {
+			return "", err
+		}
+	} else if _, err := io.Copy(h, ctxReader{ctx, f}); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ctxReader stops reading once the context is cancelled
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// hashCandidates hashes files on a bounded worker pool and groups them by hash
+func hashCandidates(ctx context.Context, files []duplicateCandidate, partial bool, workers int, progress *progressReporter, errs *[]string) map[string][]duplicateCandidate {
+	jobs := make(chan duplicateCandidate)
+	groups := make(map[string][]duplicateCandidate)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	stage := "Comparing file contents"
+	if partial {
+		stage = "Comparing file beginnings and ends"
+	}
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range jobs {
+				sum, err := hashFileContents(ctx, file.path, file.size, partial)
+
+				mu.Lock()
+				if err != nil {
+					if ctx.Err() == nil {
+						*errs = append(*errs, err.Error())
+					}
+				} else {
+					// The size is part of the key so partial hashes never match across size groups
+					key := fmt.Sprintf("%d:%s", file.size, sum)
+					groups[key] = append(groups[key], file)
+				}
+				mu.Unlock()
+
+				progress.add(float64(hashedBytes(file.size, partial)), stage)
+			}
+		}()
+	}
+
+	for _, file := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- file
+	}
+	close(jobs)
+	wg.Wait()
+
+	return groups
+}
+
+// hashedBytes returns how many bytes hashing a file of size reads
+func hashedBytes(size int64, partial bool) int64 {
+	if partial && size > 2*duplicatePartialSize {
+		return 2 * duplicatePartialSize
+	}
+	return size
+}
+
+// suggestKeeper picks the file to keep: one not named like a copy, then the oldest, then the shortest path
+func suggestKeeper(files []duplicateFile) string {
+	isCopy := func(path string) bool {
+		base := filepath.Base(path)
+		return copyMarker.MatchString(strings.TrimSuffix(base, filepath.Ext(base)))
+	}
+
+	best := files[0]
+	for _, f := range files[1:] {
+		switch bc, fc := isCopy(best.Path), isCopy(f.Path); {
+		case bc != fc:
+			if bc {
+				best = f
+			}
+		case !f.LastModifyTime.Equal(best.LastModifyTime):
+			if f.LastModifyTime.Before(best.LastModifyTime) {
+				best = f
+			}
+		case len(f.Path) < len(best.Path):
+			best = f
+		}
+	}
+	return best.Path
+}
+
+// FindDuplicates finds files with identical contents
+func FindDuplicates(ctx context.Context, req *mcp.CallToolRequest, input findDuplicatesInput) (*mcp.CallToolResult, *findDuplicatesOutput, error) {
+	if err := validateGlobs(input.Include); err != nil {
+		return nil, nil, err
+	}
+	if err := validateGlobs(input.Exclude); err != nil {
+		return nil, nil, err
+	}
+	if input.Limit < 0 || input.Workers < 0 || input.MinSize < 0 {
+		return nil, nil, fmt.Errorf("limit, workers and min_size must not be negative")
+	}
+
+	minSize := input.MinSize
+	if minSize == 0 {
+		minSize = 1
+	}
+	workers := input.Workers
+	if workers == 0 {
+		workers = min(runtime.NumCPU(), maxDuplicateWorkers)
+	}
+	workers = min(workers, maxDuplicateWorkers)
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultDuplicateLimit
+	}
+
+	dirs := input.Directories
+	if len(dirs) == 0 {
+		dir, err := userDir("Downloads")
+		if err != nil {
+			return nil, nil, err
+		}
+		dirs = []string{dir}
+	}
+
+	output := &findDuplicatesOutput{Directories: []string{}, Groups: []duplicateGroup{}}
+	progress := newProgressReporter(ctx, req)
+
+	// Stage 1: group by size, skipping hard links to a file already seen
+	bySize := make(map[int64][]duplicateCandidate)
+
+	for _, dir := range dirs {
+		root, err := expandHome(dir)
+		if err != nil {
+			return nil, nil, err
+		}
+		root, err = filepath.Abs(root)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+		}
+		if _, err := os.Stat(root); err != nil {
+			return nil, nil, fmt.Errorf("failed to read directory %s: %w", root, err)
+		}
+		output.Directories = append(output.Directories, root)
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				output.Errors = append(output.Errors, err.Error())
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			rel, _ := filepath.Rel(root, path)
+			if rel == "." {
+				return nil
+			}
+			if d.IsDir() {
+				if !input.Recursive || matchesAnyGlob(input.Exclude, d.Name(), rel) {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || matchesAnyGlob(input.Exclude, d.Name(), rel) {
+				return nil
+			}
+			if len(input.Include) > 0 && !matchesAnyGlob(input.Include, d.Name(), rel) {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				output.Errors = append(output.Errors, err.Error())
+				return nil
+			}
+			if info.Size() < minSize {
+				return nil
+			}
+
+			output.FilesScanned++
+			for _, other := range bySize[info.Size()] {
+				if os.SameFile(other.info, info) {
+					output.HardLinks++
+					return nil
+				}
+			}
+			bySize[info.Size()] = append(bySize[info.Size()], duplicateCandidate{path: path, size: info.Size(), modTime: info.ModTime(), info: info})
+			return nil
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	var candidates []duplicateCandidate
+	var total int64
+	for _, files := range bySize {
+		if len(files) > 1 {
+			candidates = append(candidates, files...)
+			for _, f := range files {
+				total += hashedBytes(f.size, true)
+			}
+		}
+	}
+
+	// Stage 2: group same-sized files by a hash of their first and last bytes
+	progress.setTotal(float64(total), fmt.Sprintf("Found %d files with a same-sized twin", len(candidates)))
+	partialGroups := hashCandidates(ctx, candidates, true, workers, progress, &output.Errors)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// Stage 3: confirm with a hash of the full contents
+	candidates = candidates[:0]
+	for _, files := range partialGroups {
+		if len(files) > 1 {
+			candidates = append(candidates, files...)
+			for _, f := range files {
+				total += f.size
+			}
+		}
+	}
+	output.FilesHashed = len(candidates)
+
+	progress.setTotal(float64(total), fmt.Sprintf("Hashing %d possible duplicates", len(candidates)))
+	fullGroups := hashCandidates(ctx, candidates, false, workers, progress, &output.Errors)
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	for key, files := range fullGroups {
+		if len(files) < 2 {
+			continue
+		}
+
+		_, sum, _ := strings.Cut(key, ":")
+		group := duplicateGroup{SHA256: sum, Size: files[0].size, Files: make([]duplicateFile, 0, len(files))}
+		for _, f := range files {
+			group.Files = append(group.Files, duplicateFile{Path: f.path, LastModifyTime: f.modTime})
+		}
+		sort.Slice(group.Files, func(i, j int) bool { return group.Files[i].Path < group.Files[j].Path })
+		group.Keeper = suggestKeeper(group.Files)
+		group.WastedBytes = group.Size * int64(len(files)-1)
+
+		output.Groups = append(output.Groups, group)
+		output.DuplicateFiles += len(files) - 1
+		output.WastedBytes += group.WastedBytes
+	}
+
+	sort.Slice(output.Groups, func(i, j int) bool {
+		if output.Groups[i].WastedBytes != output.Groups[j].WastedBytes {
+			return output.Groups[i].WastedBytes > output.Groups[j].WastedBytes
+		}
+		return output.Groups[i].Files[0].Path < output.Groups[j].Files[0].Path
+	})
+
+	output.TotalGroups = len(output.Groups)
+	if len(output.Groups) > limit {
+		output.Groups = output.Groups[:limit]
+		output.HasMore = true
+	}
+
+	return nil, output, nil
+}
@@ -0,0 +1,262 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// defaultFreePortTimeout is how long to wait after SIGTERM before sending SIGKILL
+const defaultFreePortTimeout = 5 * time.Second
+
+type freePortInput struct {
+	Port           int    `json:"port" jsonschema:"Port whose owning process should be stopped"`
+	Protocol       string `json:"protocol,omitempty" jsonschema:"Only consider sockets of this protocol ('tcp' or 'udp')"`
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty" jsonschema:"Seconds to wait after SIGTERM before sending SIGKILL, defaults to 5"`
+}
+
+type stoppedProcess struct {
+	PID     int    `json:"pid" jsonschema:"PID of the process"`
+	Name    string `json:"name" jsonschema:"Name of the process"`
+	Cmdline string `json:"cmdline" jsonschema:"Command line of the process"`
+	Signal  string `json:"signal,omitempty" jsonschema:"Last signal sent to the process (SIGTERM or SIGKILL)"`
+	Stopped bool   `json:"stopped" jsonschema:"Whether the process has exited"`
+	Error   string `json:"error,omitempty" jsonschema:"Error encountered while signalling the process"`
+}
+
+type freePortOutput struct {
+	Port      int              `json:"port" jsonschema:"Port that was freed"`
+	Confirmed bool             `json:"confirmed" jsonschema:"Whether the user confirmed stopping the processes"`
+	Processes []stoppedProcess `json:"processes" jsonschema:"Processes holding the port and what happened to them"`
+	// Unidentified sockets usually belong to processes of other users
+	Unidentified int  `json:"unidentified" jsonschema:"Sockets on the port whose owning process could not be identified and was not stopped"`
+	Free         bool `json:"free" jsonschema:"Whether the port is no longer held by any process"`
+}
+
+// portOwners returns the processes holding a listening socket on the port and
+// the number of sockets on it whose owner could not be identified
+func portOwners(root string, port int, protocol string) ([]procProcess, int, error) {
+	sockets, err := listeningSockets(root)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var owners []procProcess
+	seen := make(map[int]bool)
+	unknown := 0
+
+	for _, s := range sockets {
+		if s.Port != port || (protocol != "" && !strings.HasPrefix(s.Protocol, protocol)) {
+			continue
+		}
+		if s.PID == 0 {
+			unknown++
+			continue
+		}
+		if seen[s.PID] {
+			continue
+		}
+		seen[s.PID] = true
+		owners = append(owners, readProcProcess(root, s.PID))
+	}
+
+	return owners, unknown, nil
+}
+
+// sameProcess reports whether pid still runs the process p, and not another
+// one that was given the same PID after p exited
+func sameProcess(p procProcess) bool {
+	start, ok := procStartTime(procRoot, p.PID)
+	return ok && start == p.StartTime && !procExited(procRoot, p.PID)
+}
+
+// checkSignalAllowed refuses to signal processes this tool must never touch
+func checkSignalAllowed(p procProcess) error {
+	switch {
+	case p.PID == 1:
+		return fmt.Errorf("refusing to stop PID 1 (%s)", p.Name)
+	case p.PID == os.Getpid():
+		return fmt.Errorf("refusing to stop the MCP server itself (PID %d)", p.PID)
+	case p.UID != os.Getuid():
+		return fmt.Errorf("refusing to stop PID %d (%s): it is not owned by the current user", p.PID, p.Name)
+	}
+	return nil
+}
+
+// stopProcess sends SIGTERM and escalates to SIGKILL if the process has not exited within timeout.
+// The PID is checked against the start time of p before each signal.
+func stopProcess(ctx context.Context, p procProcess, timeout time.Duration) stoppedProcess {
+	result := stoppedProcess{PID: p.PID, Name: p.Name, Cmdline: p.Cmdline}
+
+	proc, err := os.FindProcess(p.PID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if !sameProcess(p) {
+		result.Stopped = true
+		return result
+	}
+
+	result.Signal = "SIGTERM"
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if waitProcExit(ctx, p.PID, timeout) {
+		result.Stopped = true
+		return result
+	}
+
+	// The process may have exited and its PID been reused while waiting
+	if !sameProcess(p) {
+		result.Stopped = true
+		return result
+	}
+
+	result.Signal = "SIGKILL"
+	if err := proc.Signal(syscall.SIGKILL); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Stopped = waitProcExit(ctx, p.PID, time.Second)
+
+	return result
+}
+
+// waitProcExit polls until the process exits or timeout elapses
+func waitProcExit(ctx context.Context, pid int, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		if procExited(procRoot, pid) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			return procExited(procRoot, pid)
+		case <-ticker.C:
+		}
+	}
+}
+
+// FreePort stops the processes holding a port after the user confirms it
+func FreePort(ctx context.Context, req *mcp.CallToolRequest, input freePortInput) (*mcp.CallToolResult, *freePortOutput, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil, fmt.Errorf("freeing ports is not supported on %s", runtime.GOOS)
+	}
+
+	if input.Port < 1 || input.Port > 65535 {
+		return nil, nil, fmt.Errorf("invalid port %d", input.Port)
+	}
+
+	protocol := strings.ToLower(input.Protocol)
+	if protocol != "" && protocol != "tcp" && protocol != "udp" {
+		return nil, nil, fmt.Errorf("unsupported protocol '%s', expected 'tcp' or 'udp'", input.Protocol)
+	}
+
+	timeout := defaultFreePortTimeout
+	if input.TimeoutSeconds > 0 {
+		timeout = time.Duration(input.TimeoutSeconds) * time.Second
+	}
+
+	owners, unknown, err := portOwners(procRoot, input.Port, protocol)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(owners) == 0 && unknown > 0 {
+		return nil, nil, fmt.Errorf("port %d is in use by a process that could not be identified, it probably belongs to another user", input.Port)
+	}
+
+	output := &freePortOutput{
+		Port:         input.Port,
+		Processes:    []stoppedProcess{},
+		Unidentified: unknown,
+	}
+
+	if len(owners) == 0 {
+		output.Free = true
+		return nil, output, nil
+	}
+
+	for _, p := range owners {
+		if err := checkSignalAllowed(p); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "Stop the following processes holding port %d? They will receive SIGTERM, then SIGKILL after %s.\n", input.Port, timeout)
+	for _, p := range owners {
+		fmt.Fprintf(&message, "\nPID %d (%s): %s", p.PID, p.Name, p.Cmdline)
+	}
+	if unknown > 0 {
+		fmt.Fprintf(&message, "\n\n%d more socket(s) on the port belong to processes that could not be identified and will not be stopped.", unknown)
+	}
+
+	confirmed, err := confirmAction(ctx, req, message.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output.Confirmed = confirmed
+	if !confirmed {
+		for _, p := range owners {
+			output.Processes = append(output.Processes, stoppedProcess{PID: p.PID, Name: p.Name, Cmdline: p.Cmdline})
+		}
+		return nil, output, nil
+	}
+
+	// The prompt may have been open for a while: only signal processes that still
+	// hold the port and are the same processes the user confirmed
+	current, _, err := portOwners(procRoot, input.Port, protocol)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, p := range owners {
+		result := stoppedProcess{PID: p.PID, Name: p.Name, Cmdline: p.Cmdline}
+
+		var now *procProcess
+		for i := range current {
+			if current[i].PID == p.PID && current[i].StartTime == p.StartTime {
+				now = &current[i]
+				break
+			}
+		}
+		if now == nil {
+			result.Stopped = !sameProcess(p)
+			if !result.Stopped {
+				result.Error = "no longer holds the port, not signalled"
+			}
+		} else if err := checkSignalAllowed(*now); err != nil {
+			result.Error = err.Error()
+		} else {
+			result = stopProcess(ctx, *now, timeout)
+		}
+		output.Processes = append(output.Processes, result)
+	}
+
+	// Report the port as free only if nothing, identified or not, holds it anymore
+	remaining, unknown, err := portOwners(procRoot, input.Port, protocol)
+	if err != nil {
+		return nil, nil, err
+	}
+	output.Unidentified = unknown
+	output.Free = len(remaining) == 0 && unknown == 0
+
+	return nil, output, nil
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type listConnectionsInput struct {
+	State      string `json:"state,omitempty" jsonschema:"Only return connections in this state, e.g. 'ESTABLISHED' or 'TIME_WAIT'"`
+	Process    string `json:"process,omitempty" jsonschema:"Only return connections owned by this PID or by processes whose name or command line contains this text"`
+	RemoteHost string `json:"remote_host,omitempty" jsonschema:"Only return connections to this remote IP address"`
+	Port       int    `json:"port,omitempty" jsonschema:"Only return connections whose local or remote port is this port"`
+}
+
+type connection struct {
+	Protocol      string `json:"protocol" jsonschema:"Socket protocol (tcp or tcp6)"`
+	State         string `json:"state" jsonschema:"TCP state"`
+	LocalAddress  string `json:"local_address" jsonschema:"Local address and port"`
+	RemoteAddress string `json:"remote_address" jsonschema:"Remote address and port"`
+	PID           int    `json:"pid,omitempty" jsonschema:"PID of the owning process, if it could be determined"`
+	Process       string `json:"process,omitempty" jsonschema:"Name of the owning process"`
+}
+
+type remoteHostSummary struct {
+	RemoteHost  string         `json:"remote_host" jsonschema:"Remote IP address"`
+	Connections int            `json:"connections" jsonschema:"Number of connections to the host"`
+	Ports       []int          `json:"ports" jsonschema:"Remote ports connected to"`
+	Processes   []string       `json:"processes" jsonschema:"Processes connected to the host"`
+	States      map[string]int `json:"states" jsonschema:"Number of connections per state"`
+}
+
+type processSummary struct {
+	PID         int            `json:"pid" jsonschema:"PID of the process, 0 when unknown"`
+	Process     string         `json:"process" jsonschema:"Name of the process"`
+	Connections int            `json:"connections" jsonschema:"Number of connections owned by the process"`
+	RemoteHosts []string       `json:"remote_hosts" jsonschema:"Remote hosts the process is connected to"`
+	States      map[string]int `json:"states" jsonschema:"Number of connections per state"`
+}
+
+type listConnectionsOutput struct {
+	Connections  []connection        `json:"connections" jsonschema:"TCP connections"`
+	ByRemoteHost []remoteHostSummary `json:"by_remote_host" jsonschema:"Connections aggregated by remote host"`
+	ByProcess    []processSummary    `json:"by_process" jsonschema:"Connections aggregated by owning process"`
+}
+
+// appendUnique appends s to list unless it is already present
+func appendUnique[T comparable](list []T, s T) []T {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// ListConnections lists active TCP connections with their owning process
+func ListConnections(ctx context.Context, req *mcp.CallToolRequest, input listConnectionsInput) (*mcp.CallToolResult, *listConnectionsOutput, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil, fmt.Errorf("listing connections is not supported on %s", runtime.GOOS)
+	}
+
+	var remoteFilter net.IP
+	if input.RemoteHost != "" {
+		if remoteFilter = net.ParseIP(input.RemoteHost); remoteFilter == nil {
+			return nil, nil, fmt.Errorf("invalid IP address '%s'", input.RemoteHost)
+		}
+	}
+
+	owners, err := socketOwners(procRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	processes := make(map[int]procProcess)
+	output := &listConnectionsOutput{
+		Connections:  []connection{},
+		ByRemoteHost: []remoteHostSummary{},
+		ByProcess:    []processSummary{},
+	}
+	byHost := make(map[string]*remoteHostSummary)
+	byProcess := make(map[int]*processSummary)
+
+	for _, proto := range []string{"tcp", "tcp6"} {
+		sockets, err := readProcNet(procRoot, proto)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, s := range sockets {
+			if s.State == "LISTEN" {
+				continue
+			}
+			if input.State != "" && !strings.EqualFold(s.State, input.State) {
+				continue
+			}
+			if remoteFilter != nil && !remoteFilter.Equal(s.RemoteIP) {
+				continue
+			}
+			if input.Port != 0 && s.LocalPort != input.Port && s.RemotePort != input.Port {
+				continue
+			}
+
+			c := connection{
+				Protocol:      proto,
+				State:         s.State,
+				LocalAddress:  net.JoinHostPort(s.LocalIP.String(), strconv.Itoa(s.LocalPort)),
+				RemoteAddress: net.JoinHostPort(s.RemoteIP.String(), strconv.Itoa(s.RemotePort)),
+			}
+
+			var p procProcess
+			if pid, ok := owners[s.Inode]; ok {
+				if p, ok = processes[pid]; !ok {
+					p = readProcProcess(procRoot, pid)
+					processes[pid] = p
+				}
+				c.PID = p.PID
+				c.Process = p.Name
+			}
+
+			if !matchesProcess(input.Process, p.PID, p.Name, p.Cmdline) {
+				continue
+			}
+
+			output.Connections = append(output.Connections, c)
+
+			remote := s.RemoteIP.String()
+			host, ok := byHost[remote]
+			if !ok {
+				host = &remoteHostSummary{RemoteHost: remote, Ports: []int{}, Processes: []string{}, States: map[string]int{}}
+				byHost[remote] = host
+			}
+			host.Connections++
+			host.States[s.State]++
+			host.Ports = appendUnique(host.Ports, s.RemotePort)
+			if c.Process != "" {
+				host.Processes = appendUnique(host.Processes, fmt.Sprintf("%s (%d)", c.Process, c.PID))
+			}
+
+			proc, ok := byProcess[c.PID]
+			if !ok {
+				proc = &processSummary{PID: c.PID, Process: c.Process, RemoteHosts: []string{}, States: map[string]int{}}
+				byProcess[c.PID] = proc
+			}
+			proc.Connections++
+			proc.States[s.State]++
+			proc.RemoteHosts = appendUnique(proc.RemoteHosts, remote)
+		}
+	}
+
+	for _, host := range byHost {
+		sort.Ints(host.Ports)
+		output.ByRemoteHost = append(output.ByRemoteHost, *host)
+	}
+	sort.Slice(output.ByRemoteHost, func(i, j int) bool {
+		if output.ByRemoteHost[i].Connections != output.ByRemoteHost[j].Connections {
+			return output.ByRemoteHost[i].Connections > output.ByRemoteHost[j].Connections
+		}
+		return output.ByRemoteHost[i].RemoteHost < output.ByRemoteHost[j].RemoteHost
+	})
+
+	for _, proc := range byProcess {
+		output.ByProcess = append(output.ByProcess, *proc)
+	}
+	sort.Slice(output.ByProcess, func(i, j int) bool {
+		if output.ByProcess[i].Connections != output.ByProcess[j].Connections {
+			return output.ByProcess[i].Connections > output.ByProcess[j].Connections
+		}
+		return output.ByProcess[i].PID < output.ByProcess[j].PID
+	})
+
+	return nil, output, nil
+}
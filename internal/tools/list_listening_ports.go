@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type listListeningPortsInput struct {
+	Port     int    `json:"port,omitempty" jsonschema:"Only return sockets bound to this port"`
+	Process  string `json:"process,omitempty" jsonschema:"Only return sockets owned by this PID or by processes whose name or command line contains this text"`
+	Protocol string `json:"protocol,omitempty" jsonschema:"Only return sockets of this protocol ('tcp' or 'udp')"`
+}
+
+type listeningPort struct {
+	Protocol string `json:"protocol" jsonschema:"Socket protocol (tcp, tcp6, udp, udp6)"`
+	Address  string `json:"address" jsonschema:"Local address the socket is bound to"`
+	Port     int    `json:"port" jsonschema:"Local port the socket is bound to"`
+	PID      int    `json:"pid,omitempty" jsonschema:"PID of the owning process, if it could be determined"`
+	Process  string `json:"process,omitempty" jsonschema:"Name of the owning process"`
+	Cmdline  string `json:"cmdline,omitempty" jsonschema:"Command line of the owning process"`
+}
+
+type listListeningPortsOutput struct {
+	Ports []listeningPort `json:"ports" jsonschema:"List of listening sockets"`
+}
+
+// listeningSockets returns the listening TCP sockets and unconnected UDP
+// sockets found under root, with their owning process resolved where possible.
+func listeningSockets(root string) ([]listeningPort, error) {
+	owners, err := socketOwners(root)
+	if err != nil {
+		return nil, err
+	}
+
+	processes := make(map[int]procProcess)
+	ports := []listeningPort{}
+
+	for _, proto := range []string{"tcp", "tcp6", "udp", "udp6"} {
+		sockets, err := readProcNet(root, proto)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range sockets {
+			if strings.HasPrefix(proto, "tcp") && s.State != "LISTEN" {
+				continue
+			}
+			if strings.HasPrefix(proto, "udp") && s.RemotePort != 0 {
+				continue
+			}
+
+			port := listeningPort{
+				Protocol: proto,
+				Address:  s.LocalIP.String(),
+				Port:     s.LocalPort,
+			}
+
+			if pid, ok := owners[s.Inode]; ok {
+				p, ok := processes[pid]
+				if !ok {
+					p = readProcProcess(root, pid)
+					processes[pid] = p
+				}
+				port.PID = p.PID
+				port.Process = p.Name
+				port.Cmdline = p.Cmdline
+			}
+
+			ports = append(ports, port)
+		}
+	}
+
+	sort.SliceStable(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Protocol < ports[j].Protocol
+	})
+
+	return ports, nil
+}
+
+// matchesProcess reports whether the PID, name or command line matches the filter
+func matchesProcess(filter string, pid int, name, cmdline string) bool {
+	if filter == "" {
+		return true
+	}
+
+	if n, err := strconv.Atoi(filter); err == nil {
+		return n == pid
+	}
+
+	filter = strings.ToLower(filter)
+
+	return strings.Contains(strings.ToLower(name), filter) || strings.Contains(strings.ToLower(cmdline), filter)
+}
+
+// ListListeningPorts lists listening sockets together with the process that owns them
+func ListListeningPorts(ctx context.Context, req *mcp.CallToolRequest, input listListeningPortsInput) (*mcp.CallToolResult, *listListeningPortsOutput, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil, fmt.Errorf("listing listening ports is not supported on %s", runtime.GOOS)
+	}
+
+	protocol := strings.ToLower(input.Protocol)
+	if protocol != "" && protocol != "tcp" && protocol != "udp" {
+		return nil, nil, fmt.Errorf("unsupported protocol '%s', expected 'tcp' or 'udp'", input.Protocol)
+	}
+
+	all, err := listeningSockets(procRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ports := []listeningPort{}
+
+	for _, p := range all {
+		if input.Port != 0 && p.Port != input.Port {
+			continue
+		}
+		if protocol != "" && !strings.HasPrefix(p.Protocol, protocol) {
+			continue
+		}
+		if !matchesProcess(input.Process, p.PID, p.Process, p.Cmdline) {
+			continue
+		}
+		ports = append(ports, p)
+	}
+
+	return nil, &listListeningPortsOutput{
+		Ports: ports,
+	}, nil
+}
@@ -3,59 +3,325 @@ package tools
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"runtime"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+const (
+	defaultOldFilesAge   = "3 months"
+	defaultOldFilesLimit = 100
+)
+
+type listOldDownloadsInput struct {
+	OlderThan   string   `json:"older_than,omitempty" jsonschema:"Age threshold as a duration ('90d', '3 months', 'P3M', '720h') or a date such as '2024-01-01'. Defaults to 3 months"`
+	Directories []string `json:"directories,omitempty" jsonschema:"Directories to scan, '~' is expanded. Defaults to the Downloads directory"`
+	Recursive   bool     `json:"recursive,omitempty" jsonschema:"Scan subdirectories"`
+	MaxDepth    int      `json:"max_depth,omitempty" jsonschema:"Maximum subdirectory depth when recursive, 0 for unlimited"`
+	Include     []string `json:"include,omitempty" jsonschema:"Glob patterns (e.g. '*.dmg') a file name or relative path must match"`
+	Exclude     []string `json:"exclude,omitempty" jsonschema:"Glob patterns of file names or relative paths to skip; matching directories are not scanned"`
+	MinSize     int64    `json:"min_size,omitempty" jsonschema:"Minimum file size in bytes"`
+	MaxSize     int64    `json:"max_size,omitempty" jsonschema:"Maximum file size in bytes"`
+	TimeBasis   string   `json:"time_basis,omitempty" jsonschema:"'modified', 'accessed' or 'both' (default): with 'both' a file is old only if it was neither modified nor accessed since the threshold"`
+	SortBy      string   `json:"sort_by,omitempty" jsonschema:"'modified' (default), 'accessed', 'size', 'name' or 'path'"`
+	Order       string   `json:"order,omitempty" jsonschema:"'asc' (default, oldest or smallest first) or 'desc'"`
+	Offset      int      `json:"offset,omitempty" jsonschema:"Number of matching files to skip"`
+	Limit       int      `json:"limit,omitempty" jsonschema:"Maximum number of files to return, defaults to 100"`
+}
+
 type listOldDownloadsOutput struct {
-	System string    `json:"system" jsonschema:"Operating system of the server"`
-	Files  []oldFile `json:"files" jsonschema:"List of file paths to check for old downloads"`
+	System      string    `json:"system" jsonschema:"Operating system of the server"`
+	Directories []string  `json:"directories" jsonschema:"Directories that were scanned"`
+	Cutoff      time.Time `json:"cutoff" jsonschema:"Files last used before this time are considered old"`
+	Total       int       `json:"total" jsonschema:"Number of matching files"`
+	TotalSize   int64     `json:"total_size" jsonschema:"Combined size of all matching files in bytes"`
+	Offset      int       `json:"offset" jsonschema:"Offset of the first returned file"`
+	HasMore     bool      `json:"has_more" jsonschema:"Whether more files match than were returned"`
+	Files       []oldFile `json:"files" jsonschema:"List of old files"`
+	Errors      []string  `json:"errors,omitempty" jsonschema:"Paths that could not be read"`
+	Notes       []string  `json:"notes,omitempty" jsonschema:"Notes about how the scan was done"`
 }
 
 type oldFile struct {
-	Name           string    `json:"name" jsonschema:"Name of the old file"`
-	LastModifyTime time.Time `json:"last_modify" jsonschema:"Last modify time of the file"`
-	Size           int64     `json:"size" jsonschema:"Size of the file in bytes"`
+	Name           string     `json:"name" jsonschema:"Name of the old file"`
+	Path           string     `json:"path" jsonschema:"Full path of the file"`
+	LastModifyTime time.Time  `json:"last_modify" jsonschema:"Last modify time of the file"`
+	LastAccessTime *time.Time `json:"last_access,omitempty" jsonschema:"Last access time of the file, when the OS records it"`
+	Size           int64      `json:"size" jsonschema:"Size of the file in bytes"`
 }
 
-// ListOldDownloads lists files in the Download directory that haven't been accessed in a long time.
-func ListOldDownloads(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, *listOldDownloadsOutput, error) {
-	downloadDir, err := os.UserHomeDir()
+// lastUsed returns the time the file was last used according to basis
+func (f oldFile) lastUsed(basis string) time.Time {
+	switch {
+	case basis == "modified" || f.LastAccessTime == nil:
+		return f.LastModifyTime
+	case basis == "accessed":
+		return *f.LastAccessTime
+	case f.LastAccessTime.After(f.LastModifyTime):
+		return *f.LastAccessTime
+	}
+	return f.LastModifyTime
+}
+
+// expandHome replaces a leading '~' with the user's home directory
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, `~\`) {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to get user home directory: %w", err)
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
 	}
+	return filepath.Join(home, path[1:]), nil
+}
 
-	downloadDir = downloadDir + string(os.PathSeparator) + "Downloads"
+// parseAgeThreshold turns a duration or a date into a cutoff time
+func parseAgeThreshold(s string, now time.Time) (time.Time, error) {
+	if d, err := parseCalendarDuration(s); err == nil {
+		cutoff, _ := addCalendarDuration(now, d.negate(), time.Local)
+		return cutoff, nil
+	}
+
+	if t, err := parseDateArg(s, time.Local, now); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid age threshold '%s', expected a duration such as '3 months' or '720h', or a date such as '2024-01-01'", s)
+}
 
-	files, err := os.ReadDir(downloadDir)
+// matchesAnyGlob reports whether the file name or its path relative to the
+// scanned directory matches one of the patterns
+func matchesAnyGlob(patterns []string, name, rel string) bool {
+	rel = filepath.ToSlash(rel)
+	for _, p := range patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(filepath.ToSlash(p), rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validateGlobs checks that every pattern is well formed
+func validateGlobs(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := filepath.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid glob pattern '%s': %w", p, err)
+		}
+	}
+	return nil
+}
+
+// oldFileScan is the result of scanning directories for old files
+type oldFileScan struct {
+	directories []string
+	cutoff      time.Time
+	basis       string
+	files       []oldFile
+	errors      []string
+}
+
+// scanOldFiles finds files matching the criteria of list_old_downloads
+func scanOldFiles(ctx context.Context, input listOldDownloadsInput) (*oldFileScan, error) {
+	now := time.Now()
+
+	age := input.OlderThan
+	if age == "" {
+		age = defaultOldFilesAge
+	}
+	cutoff, err := parseAgeThreshold(age, now)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read Downloads directory: %w", err)
+		return nil, err
 	}
 
-	var oldFiles []oldFile
+	basis := strings.ToLower(input.TimeBasis)
+	switch basis {
+	case "":
+		basis = "both"
+	case "modified", "accessed", "both":
+	default:
+		return nil, fmt.Errorf("unknown time_basis '%s', expected 'modified', 'accessed' or 'both'", input.TimeBasis)
+	}
 
-	cutoff := time.Now().AddDate(0, -3, 0) // 3 months ago
+	if err := validateGlobs(input.Include); err != nil {
+		return nil, err
+	}
+	if err := validateGlobs(input.Exclude); err != nil {
+		return nil, err
+	}
+	if input.MaxSize > 0 && input.MinSize > input.MaxSize {
+		return nil, fmt.Errorf("min_size %d is larger than max_size %d", input.MinSize, input.MaxSize)
+	}
 
-	for _, file := range files {
-		info, err := file.Info()
+	dirs := input.Directories
+	if len(dirs) == 0 {
+		dir, err := userDir("Downloads")
 		if err != nil {
-			continue
+			return nil, err
 		}
+		dirs = []string{dir}
+	}
 
-		if info.ModTime().Before(cutoff) {
-			oldFiles = append(oldFiles, oldFile{
-				Name:           info.Name(),
-				LastModifyTime: info.ModTime(),
-				Size:           info.Size(),
-			})
+	scan := &oldFileScan{cutoff: cutoff, basis: basis, files: []oldFile{}, directories: []string{}}
+
+	for _, dir := range dirs {
+		root, err := expandHome(dir)
+		if err != nil {
+			return nil, err
+		}
+		root, err = filepath.Abs(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
 		}
+		if _, err := os.Stat(root); err != nil {
+			return nil, fmt.Errorf("failed to read directory %s: %w", root, err)
+		}
+		scan.directories = append(scan.directories, root)
+
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				scan.errors = append(scan.errors, err.Error())
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			rel, _ := filepath.Rel(root, path)
+			if rel == "." {
+				return nil
+			}
+
+			if d.IsDir() {
+				depth := strings.Count(filepath.ToSlash(rel), "/") + 1
+				if !input.Recursive || (input.MaxDepth > 0 && depth > input.MaxDepth) || matchesAnyGlob(input.Exclude, d.Name(), rel) {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			if matchesAnyGlob(input.Exclude, d.Name(), rel) {
+				return nil
+			}
+			if len(input.Include) > 0 && !matchesAnyGlob(input.Include, d.Name(), rel) {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				scan.errors = append(scan.errors, err.Error())
+				return nil
+			}
+			if info.Size() < input.MinSize || (input.MaxSize > 0 && info.Size() > input.MaxSize) {
+				return nil
+			}
+
+			file := oldFile{Name: d.Name(), Path: path, LastModifyTime: info.ModTime(), Size: info.Size()}
+			if atime, ok := fileAccessTime(info); ok {
+				file.LastAccessTime = &atime
+			}
+
+			if file.lastUsed(basis).Before(cutoff) {
+				scan.files = append(scan.files, file)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+	}
+
+	return scan, nil
+}
+
+// sortOldFiles orders files by the given key
+func sortOldFiles(files []oldFile, by, order string) error {
+	var less func(a, b oldFile) bool
+	switch strings.ToLower(by) {
+	case "", "modified":
+		less = func(a, b oldFile) bool { return a.LastModifyTime.Before(b.LastModifyTime) }
+	case "accessed":
+		less = func(a, b oldFile) bool { return a.lastUsed("accessed").Before(b.lastUsed("accessed")) }
+	case "size":
+		less = func(a, b oldFile) bool { return a.Size < b.Size }
+	case "name":
+		less = func(a, b oldFile) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "path":
+		less = func(a, b oldFile) bool { return a.Path < b.Path }
+	default:
+		return fmt.Errorf("unknown sort_by '%s', expected 'modified', 'accessed', 'size', 'name' or 'path'", by)
+	}
+
+	switch strings.ToLower(order) {
+	case "", "asc":
+	case "desc":
+		asc := less
+		less = func(a, b oldFile) bool { return asc(b, a) }
+	default:
+		return fmt.Errorf("unknown order '%s', expected 'asc' or 'desc'", order)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool { return less(files[i], files[j]) })
+	return nil
+}
+
+// ListOldDownloads lists files in the Download directory that haven't been accessed in a long time.
+func ListOldDownloads(ctx context.Context, req *mcp.CallToolRequest, input listOldDownloadsInput) (*mcp.CallToolResult, *listOldDownloadsOutput, error) {
+	if input.Offset < 0 || input.Limit < 0 {
+		return nil, nil, fmt.Errorf("offset and limit must not be negative")
+	}
+
+	scan, err := scanOldFiles(ctx, input)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := sortOldFiles(scan.files, input.SortBy, input.Order); err != nil {
+		return nil, nil, err
+	}
+
+	limit := input.Limit
+	if limit == 0 {
+		limit = defaultOldFilesLimit
+	}
+
+	output := &listOldDownloadsOutput{
+		System:      runtime.GOOS,
+		Directories: scan.directories,
+		Cutoff:      scan.cutoff,
+		Total:       len(scan.files),
+		Offset:      input.Offset,
+		Files:       []oldFile{},
+		Errors:      scan.errors,
+	}
+
+	for _, f := range scan.files {
+		output.TotalSize += f.Size
+	}
+
+	if input.Offset < len(scan.files) {
+		end := min(input.Offset+limit, len(scan.files))
+		output.Files = scan.files[input.Offset:end]
+		output.HasMore = end < len(scan.files)
+	}
+
+	if scan.basis != "modified" && runtime.GOOS != "windows" {
+		output.Notes = append(output.Notes, "filesystems mounted with 'relatime' (the Linux default) update access times at most once a day, and 'noatime' mounts never do")
 	}
 
-	return nil, &listOldDownloadsOutput{
-		System: runtime.GOOS,
-		Files:  oldFiles,
-	}, nil
+	return nil, output, nil
 }
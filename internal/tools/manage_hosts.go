@@ -0,0 +1,375 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Markers fencing the part of the hosts file managed by these tools. Nothing
+// outside of them is ever modified.
+const (
+	hostsBlockBegin = "# BEGIN mcp-devtools"
+	hostsBlockEnd   = "# END mcp-devtools"
+)
+
+var hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9_])?(\.[a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9_])?)*\.?$`)
+
+type hostMapping struct {
+	IP       string `json:"ip" jsonschema:"IP address"`
+	Hostname string `json:"hostname" jsonschema:"Host name mapped to the IP address"`
+	Managed  bool   `json:"managed" jsonschema:"Whether the mapping is inside the mcp-devtools block"`
+}
+
+type listHostMappingsInput struct {
+	HostsFile   string `json:"hosts_file,omitempty" jsonschema:"Path of the hosts file, defaults to the system hosts file"`
+	ManagedOnly bool   `json:"managed_only,omitempty" jsonschema:"Only list mappings inside the mcp-devtools block"`
+}
+
+type listHostMappingsOutput struct {
+	HostsFile string        `json:"hosts_file" jsonschema:"Path of the hosts file"`
+	Mappings  []hostMapping `json:"mappings" jsonschema:"Host name mappings"`
+}
+
+type addHostMappingInput struct {
+	HostsFile string   `json:"hosts_file,omitempty" jsonschema:"Path of the hosts file, defaults to the system hosts file"`
+	IP        string   `json:"ip" jsonschema:"IP address to map the host names to"`
+	Hostnames []string `json:"hostnames" jsonschema:"Host names to map, e.g. 'api.local.test'"`
+}
+
+type removeHostMappingInput struct {
+	HostsFile string   `json:"hosts_file,omitempty" jsonschema:"Path of the hosts file, defaults to the system hosts file"`
+	Hostnames []string `json:"hostnames" jsonschema:"Host names to remove from the mcp-devtools block"`
+}
+
+type updateHostsOutput struct {
+	HostsFile string        `json:"hosts_file" jsonschema:"Path of the hosts file"`
+	Confirmed bool          `json:"confirmed" jsonschema:"Whether the user confirmed the change"`
+	Changed   bool          `json:"changed" jsonschema:"Whether the hosts file was written"`
+	Backup    string        `json:"backup,omitempty" jsonschema:"Path of the backup of the previous hosts file"`
+	Mappings  []hostMapping `json:"mappings" jsonschema:"Mappings inside the mcp-devtools block after the change"`
+	Warnings  []string      `json:"warnings,omitempty" jsonschema:"Conflicting mappings found outside the mcp-devtools block"`
+}
+
+// managedHosts is a hosts file split around the mcp-devtools block. The text
+// before and after the block is kept byte for byte.
+type managedHosts struct {
+	before  string
+	managed []hostMapping
+	after   string
+	// eol is the line ending used by the file, "\r\n" for most Windows hosts files
+	eol string
+}
+
+func resolveHostsPath(path string) string {
+	if path == "" {
+		return defaultHostsPath()
+	}
+	return path
+}
+
+// readManagedHosts reads a hosts file and extracts the mappings of the
+// managed block. A missing file is treated as empty.
+func readManagedHosts(path string) (*managedHosts, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read hosts file: %w", err)
+	}
+
+	content := string(data)
+	h := &managedHosts{eol: "\n"}
+	if strings.Contains(content, "\r\n") || (content == "" && runtime.GOOS == "windows") {
+		h.eol = "\r\n"
+	}
+
+	var before, after strings.Builder
+	inBlock, seenBlock := false, false
+
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if line == "" {
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == hostsBlockBegin:
+			if inBlock || seenBlock {
+				return nil, fmt.Errorf("hosts file contains more than one '%s' line", hostsBlockBegin)
+			}
+			inBlock, seenBlock = true, true
+		case trimmed == hostsBlockEnd && inBlock:
+			inBlock = false
+		case inBlock:
+			text, _, _ := strings.Cut(trimmed, "#")
+			fields := strings.Fields(text)
+			if len(fields) < 2 {
+				continue
+			}
+			for _, name := range fields[1:] {
+				h.managed = append(h.managed, hostMapping{IP: fields[0], Hostname: name, Managed: true})
+			}
+		case seenBlock:
+			after.WriteString(line)
+		default:
+			before.WriteString(line)
+		}
+	}
+
+	if inBlock {
+		return nil, fmt.Errorf("hosts file has a '%s' line without a matching '%s' line", hostsBlockBegin, hostsBlockEnd)
+	}
+
+	h.before, h.after = before.String(), after.String()
+	return h, nil
+}
+
+// unmanaged returns the mappings outside of the managed block
+func (h *managedHosts) unmanaged() []hostMapping {
+	var mappings []hostMapping
+
+	for _, line := range strings.Split(h.before+"\n"+h.after, "\n") {
+		text, _, _ := strings.Cut(line, "#")
+		fields := strings.Fields(text)
+		if len(fields) < 2 || net.ParseIP(fields[0]) == nil {
+			continue
+		}
+		for _, name := range fields[1:] {
+			mappings = append(mappings, hostMapping{IP: fields[0], Hostname: name})
+		}
+	}
+
+	return mappings
+}
+
+// render serialises the hosts file. The managed block is dropped entirely
+// once it no longer holds any mapping.
+func (h *managedHosts) render() []byte {
+	var b strings.Builder
+
+	b.WriteString(h.before)
+
+	if len(h.managed) > 0 {
+		// The block must start on a line of its own
+		if h.before != "" && !strings.HasSuffix(h.before, "\n") {
+			b.WriteString(h.eol)
+		}
+		b.WriteString(hostsBlockBegin + h.eol)
+		for _, m := range h.managed {
+			b.WriteString(m.IP + "\t" + m.Hostname + h.eol)
+		}
+		b.WriteString(hostsBlockEnd + h.eol)
+	}
+
+	b.WriteString(h.after)
+
+	return []byte(b.String())
+}
+
+// managedMappings returns a copy of the managed mappings that is never nil
+func (h *managedHosts) managedMappings() []hostMapping {
+	return append([]hostMapping{}, h.managed...)
+}
+
+// writeManagedHosts writes the hosts file after backing up the current one
+func writeManagedHosts(path string, h *managedHosts) (string, error) {
+	backup := ""
+	if _, err := os.Stat(path); err == nil {
+		backup = path + ".mcp-devtools.bak"
+		if err := copyFile(path, backup); err != nil {
+			return "", fmt.Errorf("failed to back up hosts file: %w", err)
+		}
+	}
+
+	if err := writeFileAtomic(path, h.render()); err != nil {
+		return "", fmt.Errorf("failed to write hosts file: %w", err)
+	}
+
+	return backup, nil
+}
+
+// confirmHostsUpdate applies changes to the managed block, asks the user to
+// confirm them and writes the hosts file. The file is read again after the
+// confirmation so edits made meanwhile are kept; if the changes to make are no
+// longer the ones the user confirmed, nothing is written.
+func confirmHostsUpdate(ctx context.Context, req *mcp.CallToolRequest, path string, h *managedHosts, output *updateHostsOutput, apply func(*managedHosts) []string) error {
+	changes := apply(h)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	confirmed, err := confirmAction(ctx, req, fmt.Sprintf("Update the mcp-devtools block of %s?\n\n%s", path, strings.Join(changes, "\n")))
+	if err != nil {
+		return err
+	}
+
+	output.Confirmed = confirmed
+	if !confirmed {
+		return nil
+	}
+
+	fresh, err := readManagedHosts(path)
+	if err != nil {
+		return err
+	}
+	if current := apply(fresh); strings.Join(current, "\n") != strings.Join(changes, "\n") {
+		return fmt.Errorf("%s changed while waiting for confirmation, nothing was written; run the tool again", path)
+	}
+
+	output.Backup, err = writeManagedHosts(path, fresh)
+	if err != nil {
+		return err
+	}
+	output.Changed = true
+	output.Mappings = fresh.managedMappings()
+
+	return nil
+}
+
+// validateHostnames checks the host names and normalises them to lower case
+func validateHostnames(hostnames []string) ([]string, error) {
+	if len(hostnames) == 0 {
+		return nil, fmt.Errorf("at least one host name is required")
+	}
+
+	names := make([]string, 0, len(hostnames))
+
+	for _, name := range hostnames {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if strings.Contains(name, "*") {
+			return nil, fmt.Errorf("invalid host name '%s': hosts files do not support wildcards, add each name explicitly", name)
+		}
+		if len(name) > 253 || !hostnamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid host name '%s'", name)
+		}
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// ListHostMappings lists the IP to host name mappings of the hosts file
+func ListHostMappings(ctx context.Context, req *mcp.CallToolRequest, input listHostMappingsInput) (*mcp.CallToolResult, *listHostMappingsOutput, error) {
+	path := resolveHostsPath(input.HostsFile)
+
+	h, err := readManagedHosts(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mappings := []hostMapping{}
+	if !input.ManagedOnly {
+		mappings = append(mappings, h.unmanaged()...)
+	}
+	mappings = append(mappings, h.managed...)
+
+	return nil, &listHostMappingsOutput{
+		HostsFile: path,
+		Mappings:  mappings,
+	}, nil
+}
+
+// AddHostMapping adds or updates host name mappings inside the mcp-devtools block of the hosts file
+func AddHostMapping(ctx context.Context, req *mcp.CallToolRequest, input addHostMappingInput) (*mcp.CallToolResult, *updateHostsOutput, error) {
+	path := resolveHostsPath(input.HostsFile)
+
+	ip := net.ParseIP(strings.TrimSpace(input.IP))
+	if ip == nil {
+		return nil, nil, fmt.Errorf("invalid IP address '%s'", input.IP)
+	}
+
+	names, err := validateHostnames(input.Hostnames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h, err := readManagedHosts(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &updateHostsOutput{HostsFile: path, Mappings: h.managedMappings()}
+
+	for _, m := range h.unmanaged() {
+		for _, name := range names {
+			if strings.EqualFold(m.Hostname, name) {
+				output.Warnings = append(output.Warnings, fmt.Sprintf("'%s' is also mapped to %s outside the mcp-devtools block", name, m.IP))
+			}
+		}
+	}
+
+	apply := func(h *managedHosts) []string {
+		var changes []string
+		for _, name := range names {
+			found := false
+			for i, m := range h.managed {
+				if !strings.EqualFold(m.Hostname, name) {
+					continue
+				}
+				found = true
+				if m.IP != ip.String() {
+					changes = append(changes, fmt.Sprintf("change %s from %s to %s", m.Hostname, m.IP, ip))
+					h.managed[i].IP = ip.String()
+				}
+			}
+			if !found {
+				changes = append(changes, fmt.Sprintf("add %s %s", ip, name))
+				h.managed = append(h.managed, hostMapping{IP: ip.String(), Hostname: name, Managed: true})
+			}
+		}
+		return changes
+	}
+
+	if err := confirmHostsUpdate(ctx, req, path, h, output, apply); err != nil {
+		return nil, nil, err
+	}
+	return nil, output, nil
+}
+
+// RemoveHostMapping removes host name mappings from the mcp-devtools block of the hosts file
+func RemoveHostMapping(ctx context.Context, req *mcp.CallToolRequest, input removeHostMappingInput) (*mcp.CallToolResult, *updateHostsOutput, error) {
+	path := resolveHostsPath(input.HostsFile)
+
+	names, err := validateHostnames(input.Hostnames)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h, err := readManagedHosts(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &updateHostsOutput{HostsFile: path, Mappings: h.managedMappings()}
+
+	apply := func(h *managedHosts) []string {
+		var changes []string
+		kept := []hostMapping{}
+		for _, m := range h.managed {
+			remove := false
+			for _, name := range names {
+				if strings.EqualFold(m.Hostname, name) {
+					remove = true
+					break
+				}
+			}
+			if remove {
+				changes = append(changes, fmt.Sprintf("remove %s %s", m.IP, m.Hostname))
+			} else {
+				kept = append(kept, m)
+			}
+		}
+		h.managed = kept
+		return changes
+	}
+
+	if err := confirmHostsUpdate(ctx, req, path, h, output, apply); err != nil {
+		return nil, nil, err
+	}
+	return nil, output, nil
+}
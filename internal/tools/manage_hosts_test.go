@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestManagedHostsRender(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		managed []hostMapping
+		want    string
+	}{
+		{
+			name:    "CRLF file keeps its line endings",
+			content: "127.0.0.1  localhost\r\n# comment \r\n\r\n",
+			managed: []hostMapping{{IP: "10.0.0.1", Hostname: "api.test"}},
+			want:    "127.0.0.1  localhost\r\n# comment \r\n\r\n# BEGIN mcp-devtools\r\n10.0.0.1\tapi.test\r\n# END mcp-devtools\r\n",
+		},
+		{
+			name:    "text around an existing block is untouched",
+			content: "127.0.0.1 localhost\r\n# BEGIN mcp-devtools\r\n10.0.0.1\told.test\r\n# END mcp-devtools\r\n::1\tlocalhost  \r\n",
+			managed: []hostMapping{{IP: "10.0.0.2", Hostname: "new.test"}},
+			want:    "127.0.0.1 localhost\r\n# BEGIN mcp-devtools\r\n10.0.0.2\tnew.test\r\n# END mcp-devtools\r\n::1\tlocalhost  \r\n",
+		},
+		{
+			name:    "removing the last mapping drops the block",
+			content: "127.0.0.1 localhost\n# BEGIN mcp-devtools\n10.0.0.1 old.test\n# END mcp-devtools\n::1 localhost",
+			want:    "127.0.0.1 localhost\n::1 localhost",
+		},
+		{
+			name:    "missing final newline",
+			content: "127.0.0.1 localhost",
+			managed: []hostMapping{{IP: "10.0.0.1", Hostname: "api.test"}},
+			want:    "127.0.0.1 localhost\n# BEGIN mcp-devtools\n10.0.0.1\tapi.test\n# END mcp-devtools\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "hosts")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			h, err := readManagedHosts(path)
+			if err != nil {
+				t.Fatalf("readManagedHosts: %v", err)
+			}
+			h.managed = tt.managed
+
+			if got := string(h.render()); got != tt.want {
+				t.Errorf("render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManagedHostsUnmanaged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	content := "127.0.0.1 localhost # loopback\r\n# BEGIN mcp-devtools\r\n10.0.0.1 api.test\r\n# END mcp-devtools\r\n::1 ip6-localhost ip6-loopback\r\nnot-an-ip name\r\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := readManagedHosts(path)
+	if err != nil {
+		t.Fatalf("readManagedHosts: %v", err)
+	}
+
+	want := []hostMapping{
+		{IP: "127.0.0.1", Hostname: "localhost"},
+		{IP: "::1", Hostname: "ip6-localhost"},
+		{IP: "::1", Hostname: "ip6-loopback"},
+	}
+	got := h.unmanaged()
+	if len(got) != len(want) {
+		t.Fatalf("unmanaged() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("unmanaged()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if len(h.managed) != 1 || h.managed[0].Hostname != "api.test" {
+		t.Errorf("managed = %+v, want api.test", h.managed)
+	}
+}
+
+func TestReadManagedHostsErrors(t *testing.T) {
+	tests := map[string]string{
+		"unterminated block": "# BEGIN mcp-devtools\n10.0.0.1 api.test\n",
+		"two blocks":         "# BEGIN mcp-devtools\n# END mcp-devtools\n# BEGIN mcp-devtools\n# END mcp-devtools\n",
+	}
+
+	for name, content := range tests {
+		path := filepath.Join(t.TempDir(), "hosts")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := readManagedHosts(path); err == nil {
+			t.Errorf("%s: readManagedHosts succeeded, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// mountInfo is one line of /proc/<pid>/mountinfo
+type mountInfo struct {
+	ID           int
+	ParentID     int
+	Major, Minor int
+	Root         string
+	MountPoint   string
+	Options      string
+	FSType       string
+	Source       string
+	SuperOptions string
+}
+
+// unescapeMountField decodes the octal escapes (\040 for space) used in mountinfo
+func unescapeMountField(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// readMountInfo parses root/self/mountinfo
+func readMountInfo(root string) ([]mountInfo, error) {
+	f, err := os.Open(filepath.Join(root, "self", "mountinfo"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mount table: %w", err)
+	}
+	defer f.Close()
+
+	var mounts []mountInfo
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(scanner.Text())
+		sep := -1
+		for i, f := range fields {
+			if f == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 6 || len(fields) < sep+3 {
+			continue
+		}
+
+		m := mountInfo{
+			Root:       unescapeMountField(fields[3]),
+			MountPoint: unescapeMountField(fields[4]),
+			Options:    fields[5],
+			FSType:     fields[sep+1],
+			Source:     unescapeMountField(fields[sep+2]),
+		}
+		m.ID, _ = strconv.Atoi(fields[0])
+		m.ParentID, _ = strconv.Atoi(fields[1])
+		if major, minor, ok := strings.Cut(fields[2], ":"); ok {
+			m.Major, _ = strconv.Atoi(major)
+			m.Minor, _ = strconv.Atoi(minor)
+		}
+		if len(fields) > sep+3 {
+			m.SuperOptions = fields[sep+3]
+		}
+
+		mounts = append(mounts, m)
+	}
+
+	return mounts, scanner.Err()
+}
+
+// mountForPath returns the mount a path lives on: the entry with the longest
+// matching mount point, preferring later (overmounting) entries
+func mountForPath(mounts []mountInfo, path string) (mountInfo, bool) {
+	var best mountInfo
+	found := false
+	for _, m := range mounts {
+		mp := m.MountPoint
+		if path != mp && mp != "/" && !strings.HasPrefix(path, mp+"/") {
+			continue
+		}
+		if !found || len(mp) >= len(best.MountPoint) {
+			best, found = m, true
+		}
+	}
+	return best, found
+}
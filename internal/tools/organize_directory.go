@@ -0,0 +1,401 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	organizeJournalPrefix = ".organize-journal-"
+	sniffLength           = 512
+)
+
+// partialDownloadExts are files a browser is still writing
+var partialDownloadExts = map[string]bool{
+	".crdownload": true, ".part": true, ".partial": true, ".download": true, ".opdownload": true, ".tmp": true,
+}
+
+// magicSignatures recognises formats http.DetectContentType does not know
+var magicSignatures = []struct {
+	prefix   []byte
+	mimeType string
+}{
+	{[]byte("\x7fELF"), "application/x-executable"},
+	{[]byte("\xcf\xfa\xed\xfe"), "application/x-mach-binary"},
+	{[]byte("\xca\xfe\xba\xbe"), "application/x-mach-binary"},
+	{[]byte("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1"), "application/x-ole-storage"},
+	{[]byte("7z\xbc\xaf\x27\x1c"), "application/x-7z-compressed"},
+	{[]byte("\xfd7zXZ\x00"), "application/x-xz"},
+	{[]byte("\x28\xb5\x2f\xfd"), "application/zstd"},
+	{[]byte("BZh"), "application/x-bzip2"},
+	{[]byte("!<arch>\ndebian"), "application/vnd.debian.binary-package"},
+	{[]byte("\xed\xab\xee\xdb"), "application/x-rpm"},
+}
+
+type organizeDirectoryInput struct {
+	Directory     string `json:"directory,omitempty" jsonschema:"Directory to organize, '~' is expanded. Defaults to the Downloads directory"`
+	Apply         bool   `json:"apply,omitempty" jsonschema:"Move the files after confirmation; without it only the planned moves are returned"`
+	MoveOther     bool   `json:"move_other,omitempty" jsonschema:"Also move files that fit no category into an 'Other' folder"`
+	UndoJournal   string `json:"undo_journal,omitempty" jsonschema:"Path of a journal written by an earlier run; moves its files back instead of organizing"`
+	IncludeHidden bool   `json:"include_hidden,omitempty" jsonschema:"Also organize hidden files"`
+}
+
+type organizeMove struct {
+	File         string `json:"file" jsonschema:"File name"`
+	Category     string `json:"category" jsonschema:"Detected category"`
+	ContentType  string `json:"content_type,omitempty" jsonschema:"MIME type sniffed from the file contents"`
+	Destination  string `json:"destination,omitempty" jsonschema:"Where the file goes"`
+	Renamed      bool   `json:"renamed,omitempty" jsonschema:"Whether the file gets a new name because the destination name was taken"`
+	Note         string `json:"note,omitempty" jsonschema:"Remarks, e.g. when the contents do not match the extension"`
+	Error        string `json:"error,omitempty" jsonschema:"Why the file was not moved"`
+	Moved        bool   `json:"moved" jsonschema:"Whether the file was moved"`
+	originalPath string
+}
+
+type organizeDirectoryOutput struct {
+	Directory  string         `json:"directory" jsonschema:"Directory that was organized"`
+	DryRun     bool           `json:"dry_run" jsonschema:"Whether no files were moved"`
+	Confirmed  bool           `json:"confirmed" jsonschema:"Whether the user confirmed the moves"`
+	Moves      []organizeMove `json:"moves" jsonschema:"Planned or performed moves"`
+	ByCategory map[string]int `json:"by_category" jsonschema:"Number of files per category"`
+	Moved      int            `json:"moved" jsonschema:"Number of files moved"`
+	Skipped    []string       `json:"skipped,omitempty" jsonschema:"Files left alone: hidden files, partial downloads, symlinks and uncategorized files"`
+	Journal    string         `json:"journal,omitempty" jsonschema:"Undo journal; pass it as undo_journal to move the files back"`
+	Error      string         `json:"error,omitempty" jsonschema:"Why the run stopped early, or why an undo could not update the journal; the moves made until then are listed and recorded in the journal"`
+}
+
+// organizeJournal records performed moves so they can be undone
+type organizeJournal struct {
+	Directory string                `json:"directory"`
+	Created   time.Time             `json:"created"`
+	Moves     []organizeJournalMove `json:"moves"`
+	Folders   []string              `json:"created_folders,omitempty"`
+}
+
+type organizeJournalMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// sniffContentType returns the MIME type of a file's first bytes
+func sniffContentType(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffLength)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	buf = buf[:n]
+
+	for _, sig := range magicSignatures {
+		if bytes.HasPrefix(buf, sig.prefix) {
+			return sig.mimeType, nil
+		}
+	}
+	return http.DetectContentType(buf), nil
+}
+
+// classifyFile picks a category from the extension, falling back to the contents
+func classifyFile(path string) (category, contentType, note string) {
+	byExt := categoryForExtension(fileExtension(filepath.Base(path)))
+
+	contentType, err := sniffContentType(path)
+	if err != nil {
+		return byExt, "", ""
+	}
+
+	byContent := "other"
+	switch contentType {
+	case "application/x-executable", "application/x-mach-binary":
+		byContent = "installers"
+	case "application/x-ole-storage":
+		// Old Office documents and MSI installers share this container
+		byContent = byExt
+	case "application/octet-stream":
+	default:
+		byContent = categoryForMIME(contentType)
+	}
+
+	switch {
+	case byExt == "other":
+		return byContent, contentType, ""
+	case byContent == "other" || byContent == byExt:
+		return byExt, contentType, ""
+	case byExt == "documents" && strings.HasPrefix(contentType, "text/html"):
+		// A failed download often saves the server's error page under the expected name
+		return byExt, contentType, "contents look like an HTML page, the download may have failed"
+	case byExt == "installers" && byContent == "archives", byExt == "documents" && byContent == "archives":
+		// .docx, .xlsx, .apk and .msix are zip files
+		return byExt, contentType, ""
+	}
+	return byExt, contentType, fmt.Sprintf("extension suggests %s but contents look like %s", byExt, byContent)
+}
+
+// categoryFolder returns the folder name of a category, e.g. "Images"
+func categoryFolder(category string) string {
+	return strings.ToUpper(category[:1]) + category[1:]
+}
+
+// freeName returns a path in dir for name that is not taken, adding " (n)" before the extension
+func freeName(dir, name string, reserved map[string]bool) (string, bool) {
+	split := len(name) - len(fileExtension(name))
+	stem, ext := name[:split], name[split:]
+	if stem == "" {
+		stem, ext = name, ""
+	}
+
+	for i := 0; ; i++ {
+		candidate := name
+		if i > 0 {
+			candidate = fmt.Sprintf("%s (%d)%s", stem, i, ext)
+		}
+		path := filepath.Join(dir, candidate)
+		if reserved[path] {
+			continue
+		}
+		if _, err := os.Lstat(path); errors.Is(err, fs.ErrNotExist) {
+			reserved[path] = true
+			return path, i > 0
+		}
+	}
+}
+
+// undoOrganize moves the files recorded in a journal back
+func undoOrganize(ctx context.Context, req *mcp.CallToolRequest, journalPath string) (*organizeDirectoryOutput, error) {
+	path, err := expandHome(journalPath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	var journal organizeJournal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return nil, fmt.Errorf("failed to parse journal %s: %w", path, err)
+	}
+
+	output := &organizeDirectoryOutput{Directory: journal.Directory, Moves: []organizeMove{}, ByCategory: map[string]int{}, Journal: path}
+	for _, m := range journal.Moves {
+		output.Moves = append(output.Moves, organizeMove{
+			File:         filepath.Base(m.To),
+			Category:     filepath.Base(filepath.Dir(m.To)),
+			Destination:  m.From,
+			originalPath: m.To,
+		})
+	}
+
+	confirmed, err := confirmAction(ctx, req, fmt.Sprintf("Move %d file(s) back to %s?", len(output.Moves), journal.Directory))
+	if err != nil {
+		return nil, err
+	}
+	output.Confirmed = confirmed
+	if !confirmed {
+		output.DryRun = true
+		return output, nil
+	}
+
+	// Undo in reverse order so chains of moves unwind correctly
+	for i := len(output.Moves) - 1; i >= 0; i-- {
+		move := &output.Moves[i]
+		if _, err := os.Lstat(move.Destination); err == nil {
+			move.Error = "a file with the original name exists again"
+			continue
+		}
+		if err := os.Rename(move.originalPath, move.Destination); err != nil {
+			move.Error = err.Error()
+			continue
+		}
+		move.Moved = true
+		output.Moved++
+	}
+
+	// Remove category folders the run created if they are empty now
+	var folders []string
+	for _, folder := range journal.Folders {
+		if err := os.Remove(folder); err != nil && !os.IsNotExist(err) {
+			folders = append(folders, folder)
+		}
+	}
+
+	if output.Moved == len(output.Moves) {
+		os.Remove(path)
+		output.Journal = ""
+		return output, nil
+	}
+
+	// Keep only the moves that failed, so the next undo retries just those
+	var remaining []organizeJournalMove
+	for i, m := range journal.Moves {
+		if !output.Moves[i].Moved {
+			remaining = append(remaining, m)
+		}
+	}
+	journal.Moves = remaining
+	journal.Folders = folders
+	data, err = json.MarshalIndent(journal, "", "  ")
+	if err == nil {
+		err = writeFileAtomic(path, data)
+	}
+	if err != nil {
+		output.Error = fmt.Sprintf("failed to update undo journal, it still lists the %d file(s) moved back: %v", output.Moved, err)
+	}
+
+	return output, nil
+}
+
+// OrganizeDirectory sorts the files of a directory into category folders
+func OrganizeDirectory(ctx context.Context, req *mcp.CallToolRequest, input organizeDirectoryInput) (*mcp.CallToolResult, *organizeDirectoryOutput, error) {
+	if input.UndoJournal != "" {
+		output, err := undoOrganize(ctx, req, input.UndoJournal)
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, output, nil
+	}
+
+	dir := input.Directory
+	if dir == "" {
+		downloads, err := userDir("Downloads")
+		if err != nil {
+			return nil, nil, err
+		}
+		dir = downloads
+	}
+	dir, err := expandHome(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dir, err = filepath.Abs(dir); err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve %s: %w", input.Directory, err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	output := &organizeDirectoryOutput{Directory: dir, DryRun: true, Moves: []organizeMove{}, ByCategory: map[string]int{}}
+	reserved := make(map[string]bool)
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		name := entry.Name()
+		switch {
+		case entry.IsDir():
+			continue
+		case strings.HasPrefix(name, organizeJournalPrefix):
+			continue
+		case !entry.Type().IsRegular(), strings.HasPrefix(name, ".") && !input.IncludeHidden, partialDownloadExts[strings.ToLower(filepath.Ext(name))]:
+			output.Skipped = append(output.Skipped, name)
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		category, contentType, note := classifyFile(path)
+		output.ByCategory[category]++
+
+		if category == "other" && !input.MoveOther {
+			output.Skipped = append(output.Skipped, name)
+			continue
+		}
+
+		move := organizeMove{File: name, Category: category, ContentType: contentType, Note: note, originalPath: path}
+		move.Destination, move.Renamed = freeName(filepath.Join(dir, categoryFolder(category)), name, reserved)
+		output.Moves = append(output.Moves, move)
+	}
+
+	if !input.Apply || len(output.Moves) == 0 {
+		return nil, output, nil
+	}
+
+	confirmed, err := confirmAction(ctx, req, fmt.Sprintf("Move %d file(s) in %s into category folders?", len(output.Moves), dir))
+	if err != nil {
+		return nil, nil, err
+	}
+	output.Confirmed = confirmed
+	if !confirmed {
+		return nil, output, nil
+	}
+	output.DryRun = false
+
+	journal := organizeJournal{Directory: dir, Created: time.Now()}
+
+	// Reserve a unique journal name so runs in the same second keep their own journal
+	f, err := os.CreateTemp(dir, organizeJournalPrefix+journal.Created.Format("20060102-150405")+"-*.json")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create undo journal: %w", err)
+	}
+	f.Close()
+	journalPath := f.Name()
+
+	writeJournal := func() error {
+		data, err := json.MarshalIndent(journal, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(journalPath, data)
+	}
+
+	for i := range output.Moves {
+		move := &output.Moves[i]
+
+		folder := filepath.Dir(move.Destination)
+		if _, err := os.Stat(folder); errors.Is(err, fs.ErrNotExist) {
+			if err := os.Mkdir(folder, 0o755); err != nil {
+				move.Error = err.Error()
+				continue
+			}
+			journal.Folders = append(journal.Folders, folder)
+		}
+		// The name was free when planned; check again in case something appeared since
+		if _, err := os.Lstat(move.Destination); err == nil {
+			move.Destination, move.Renamed = freeName(filepath.Dir(move.Destination), move.File, reserved)
+		}
+		if err := os.Rename(move.originalPath, move.Destination); err != nil {
+			move.Error = err.Error()
+			continue
+		}
+		move.Moved = true
+		output.Moved++
+
+		journal.Moves = append(journal.Moves, organizeJournalMove{From: move.originalPath, To: move.Destination})
+
+		// Keep the journal current so an interrupted run can still be undone.
+		// Without a journal further moves could not be undone, so stop here.
+		if err := writeJournal(); err != nil {
+			output.Error = fmt.Sprintf("stopped after %d move(s), failed to write undo journal, the move of %s is not recorded in it: %v", output.Moved, move.File, err)
+			break
+		}
+	}
+
+	if output.Moved > 0 {
+		output.Journal = journalPath
+	} else {
+		os.Remove(journalPath)
+	}
+
+	sort.Strings(output.Skipped)
+	return nil, output, nil
+}
@@ -0,0 +1,274 @@
+package tools
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultProbeTimeout      = 10 * time.Second
+	defaultProbeMaxBodyBytes = 4096
+)
+
+type probeEndpointInput struct {
+	Address            string            `json:"address,omitempty" jsonschema:"host:port to test for TCP connectivity, e.g. 'localhost:3000'. Derived from url when omitted"`
+	URL                string            `json:"url,omitempty" jsonschema:"http:// or https:// URL to request after the TCP check"`
+	Method             string            `json:"method,omitempty" jsonschema:"HTTP method, defaults to GET"`
+	Headers            map[string]string `json:"headers,omitempty" jsonschema:"HTTP request headers"`
+	Body               string            `json:"body,omitempty" jsonschema:"HTTP request body"`
+	TimeoutSeconds     int               `json:"timeout_seconds,omitempty" jsonschema:"Timeout for the TCP check and for the HTTP request in seconds, defaults to 10"`
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty" jsonschema:"Do not verify the server's TLS certificate"`
+	FollowRedirects    bool              `json:"follow_redirects,omitempty" jsonschema:"Follow HTTP redirects instead of returning the redirect response"`
+	MaxBodyBytes       int               `json:"max_body_bytes,omitempty" jsonschema:"Maximum number of response body bytes to return, defaults to 4096"`
+}
+
+type probeTiming struct {
+	DNSMs     float64 `json:"dns_ms" jsonschema:"Time spent resolving the host name in milliseconds"`
+	ConnectMs float64 `json:"connect_ms" jsonschema:"Time spent establishing the TCP connection in milliseconds"`
+	TLSMs     float64 `json:"tls_ms" jsonschema:"Time spent in the TLS handshake in milliseconds"`
+	TTFBMs    float64 `json:"ttfb_ms" jsonschema:"Time from sending the request to the first response byte in milliseconds"`
+	TotalMs   float64 `json:"total_ms" jsonschema:"Total request time including reading the body in milliseconds"`
+}
+
+type probeTLS struct {
+	Version     string     `json:"version" jsonschema:"Negotiated TLS version"`
+	CipherSuite string     `json:"cipher_suite" jsonschema:"Negotiated cipher suite"`
+	ServerName  string     `json:"server_name,omitempty" jsonschema:"Server name sent in the handshake"`
+	Subject     string     `json:"subject,omitempty" jsonschema:"Subject of the leaf certificate"`
+	Issuer      string     `json:"issuer,omitempty" jsonschema:"Issuer of the leaf certificate"`
+	NotAfter    *time.Time `json:"not_after,omitempty" jsonschema:"Expiry time of the leaf certificate"`
+}
+
+type probeHTTPResult struct {
+	Status        int               `json:"status,omitempty" jsonschema:"HTTP status code"`
+	StatusText    string            `json:"status_text,omitempty" jsonschema:"HTTP status line text"`
+	Proto         string            `json:"proto,omitempty" jsonschema:"HTTP protocol version"`
+	Headers       map[string]string `json:"headers,omitempty" jsonschema:"Response headers, multiple values are joined with ', '"`
+	Body          string            `json:"body,omitempty" jsonschema:"Response body, truncated to max_body_bytes"`
+	BodyTruncated bool              `json:"body_truncated,omitempty" jsonschema:"Whether the body was truncated"`
+	Timing        probeTiming       `json:"timing" jsonschema:"Timing breakdown of the request"`
+	TLS           *probeTLS         `json:"tls,omitempty" jsonschema:"TLS connection details for HTTPS requests"`
+	Error         string            `json:"error,omitempty" jsonschema:"Error encountered while performing the request"`
+}
+
+type probeEndpointOutput struct {
+	Address   string           `json:"address" jsonschema:"Address that was probed"`
+	Reachable bool             `json:"reachable" jsonschema:"Whether a TCP connection could be established"`
+	ConnectMs float64          `json:"connect_ms" jsonschema:"Time to establish the TCP connection in milliseconds, including name resolution"`
+	Error     string           `json:"error,omitempty" jsonschema:"Error encountered while connecting"`
+	HTTP      *probeHTTPResult `json:"http,omitempty" jsonschema:"Result of the HTTP request, if a URL was given"`
+}
+
+// millis converts a duration to fractional milliseconds
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// probeAddress derives host:port from a URL, using the scheme's default port
+func probeAddress(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	if u.Scheme == "https" {
+		return net.JoinHostPort(u.Hostname(), "443")
+	}
+	return net.JoinHostPort(u.Hostname(), "80")
+}
+
+// probeHTTP performs the HTTP request and records its timing
+func probeHTTP(ctx context.Context, u *url.URL, input probeEndpointInput, timeout time.Duration) *probeHTTPResult {
+	result := &probeHTTPResult{}
+
+	method := strings.ToUpper(input.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	maxBody := input.MaxBodyBytes
+	if maxBody <= 0 {
+		maxBody = defaultProbeMaxBodyBytes
+	}
+
+	var body io.Reader
+	if input.Body != "" {
+		body = strings.NewReader(input.Body)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Trace callbacks run on the transport's goroutines, and with dual-stack
+	// hosts several connection attempts race each other, so the timings are
+	// guarded and only the first successful connection is recorded
+	var mu sync.Mutex
+	var timing probeTiming
+	var dnsStart, tlsStart, wroteRequest time.Time
+	connectStarts := make(map[string]time.Time)
+	connected := false
+	record := func(f func()) {
+		mu.Lock()
+		defer mu.Unlock()
+		f()
+	}
+
+	trace := &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { record(func() { dnsStart = time.Now() }) },
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			record(func() { timing.DNSMs = millis(time.Since(dnsStart)) })
+		},
+		ConnectStart: func(network, addr string) {
+			record(func() { connectStarts[network+" "+addr] = time.Now() })
+		},
+		ConnectDone: func(network, addr string, err error) {
+			record(func() {
+				if err == nil && !connected {
+					connected = true
+					timing.ConnectMs = millis(time.Since(connectStarts[network+" "+addr]))
+				}
+			})
+		},
+		TLSHandshakeStart: func() { record(func() { tlsStart = time.Now() }) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			record(func() { timing.TLSMs = millis(time.Since(tlsStart)) })
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) { record(func() { wroteRequest = time.Now() }) },
+		GotFirstResponseByte: func() {
+			record(func() { timing.TTFBMs = millis(time.Since(wroteRequest)) })
+		},
+	}
+	timingSoFar := func() probeTiming {
+		mu.Lock()
+		defer mu.Unlock()
+		return timing
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), method, u.String(), body)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	for k, v := range input.Headers {
+		req.Header.Set(k, v)
+		if strings.EqualFold(k, "Host") {
+			req.Host = v
+		}
+	}
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: input.InsecureSkipVerify},
+		},
+	}
+	if !input.FollowRedirects {
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+
+	start := time.Now()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Timing = timingSoFar()
+		result.Timing.TotalMs = millis(time.Since(start))
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxBody)+1))
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to read response body: %v", err)
+	}
+	result.Timing = timingSoFar()
+	result.Timing.TotalMs = millis(time.Since(start))
+
+	if len(data) > maxBody {
+		data = data[:maxBody]
+		result.BodyTruncated = true
+	}
+
+	result.Status = resp.StatusCode
+	result.StatusText = http.StatusText(resp.StatusCode)
+	result.Proto = resp.Proto
+	result.Body = string(data)
+	result.Headers = make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		result.Headers[k] = strings.Join(v, ", ")
+	}
+
+	if resp.TLS != nil {
+		result.TLS = &probeTLS{
+			Version:     tls.VersionName(resp.TLS.Version),
+			CipherSuite: tls.CipherSuiteName(resp.TLS.CipherSuite),
+			ServerName:  resp.TLS.ServerName,
+		}
+		if len(resp.TLS.PeerCertificates) > 0 {
+			leaf := resp.TLS.PeerCertificates[0]
+			result.TLS.Subject = leaf.Subject.String()
+			result.TLS.Issuer = leaf.Issuer.String()
+			result.TLS.NotAfter = &leaf.NotAfter
+		}
+	}
+
+	return result
+}
+
+// ProbeEndpoint checks TCP reachability of an endpoint and optionally performs an HTTP request against it
+func ProbeEndpoint(ctx context.Context, req *mcp.CallToolRequest, input probeEndpointInput) (*mcp.CallToolResult, *probeEndpointOutput, error) {
+	timeout := defaultProbeTimeout
+	if input.TimeoutSeconds > 0 {
+		timeout = time.Duration(input.TimeoutSeconds) * time.Second
+	}
+
+	var u *url.URL
+	if input.URL != "" {
+		var err error
+		u, err = url.Parse(input.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, nil, fmt.Errorf("invalid URL '%s', expected an http:// or https:// URL", input.URL)
+		}
+	}
+
+	address := input.Address
+	if address == "" {
+		if u == nil {
+			return nil, nil, fmt.Errorf("either address or url must be given")
+		}
+		address = probeAddress(u)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, nil, fmt.Errorf("invalid address '%s', expected host:port", address)
+	}
+
+	output := &probeEndpointOutput{Address: address}
+
+	dialer := net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	output.ConnectMs = millis(time.Since(start))
+	if err != nil {
+		output.Error = err.Error()
+		return nil, output, nil
+	}
+	conn.Close()
+	output.Reachable = true
+
+	if u != nil {
+		output.HTTP = probeHTTP(ctx, u, input, timeout)
+	}
+
+	return nil, output, nil
+}
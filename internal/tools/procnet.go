@@ -0,0 +1,264 @@
+package tools
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// procRoot is the mount point of the proc filesystem. It is a variable so the
+// parsers below can be pointed at a fixture tree.
+var procRoot = "/proc"
+
+// tcpStates maps the hex state codes used in /proc/net/tcp to their names
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+	"0C": "NEW_SYN_RECV",
+}
+
+// procSocket is a single entry of /proc/net/{tcp,tcp6,udp,udp6}
+type procSocket struct {
+	Protocol   string
+	LocalIP    net.IP
+	LocalPort  int
+	RemoteIP   net.IP
+	RemotePort int
+	State      string
+	UID        int
+	Inode      uint64
+}
+
+// procProcess describes a process found under /proc/<pid>
+type procProcess struct {
+	PID     int
+	UID     int
+	Name    string
+	Cmdline string
+	// StartTime is field 22 of /proc/<pid>/stat, in clock ticks since boot. Together
+	// with the PID it identifies a process even after the PID is reused.
+	StartTime uint64
+}
+
+// readProcNet parses /proc/net/<proto> under root. A missing table (for
+// example tcp6 on a kernel without IPv6) yields no sockets and no error.
+func readProcNet(root, proto string) ([]procSocket, error) {
+	f, err := os.Open(filepath.Join(root, "net", proto))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open /proc/net/%s: %w", proto, err)
+	}
+	defer f.Close()
+
+	var sockets []procSocket
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // skip header line
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+
+		localIP, localPort, err := parseProcNetAddr(fields[1])
+		if err != nil {
+			continue
+		}
+		remoteIP, remotePort, err := parseProcNetAddr(fields[2])
+		if err != nil {
+			continue
+		}
+
+		state := strings.ToUpper(fields[3])
+		if name, ok := tcpStates[state]; ok {
+			state = name
+		}
+
+		uid, _ := strconv.Atoi(fields[7])
+		inode, _ := strconv.ParseUint(fields[9], 10, 64)
+
+		sockets = append(sockets, procSocket{
+			Protocol:   proto,
+			LocalIP:    localIP,
+			LocalPort:  localPort,
+			RemoteIP:   remoteIP,
+			RemotePort: remotePort,
+			State:      state,
+			UID:        uid,
+			Inode:      inode,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read /proc/net/%s: %w", proto, err)
+	}
+
+	return sockets, nil
+}
+
+// parseProcNetAddr parses an "ADDR:PORT" pair as written by the kernel. The
+// address is stored as 32-bit words in host (little-endian) byte order and
+// the port as big-endian hex.
+func parseProcNetAddr(s string) (net.IP, int, error) {
+	addrHex, portHex, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, 0, fmt.Errorf("invalid address %q", s)
+	}
+
+	raw, err := hex.DecodeString(addrHex)
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("invalid address %q", s)
+	}
+
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+
+	port, err := strconv.ParseUint(portHex, 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid port in %q", s)
+	}
+
+	return ip, int(port), nil
+}
+
+// socketOwners maps socket inodes to the PID holding them open. Processes
+// whose fd directory cannot be read (typically those of other users) are
+// skipped.
+func socketOwners(root string) (map[uint64]int, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", root, err)
+	}
+
+	owners := make(map[uint64]int)
+
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		fdDir := filepath.Join(root, entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil {
+				continue
+			}
+
+			inode, ok := parseSocketLink(link)
+			if !ok {
+				continue
+			}
+
+			if _, seen := owners[inode]; !seen {
+				owners[inode] = pid
+			}
+		}
+	}
+
+	return owners, nil
+}
+
+// parseSocketLink extracts the inode from a "socket:[12345]" fd link
+func parseSocketLink(link string) (uint64, bool) {
+	if !strings.HasPrefix(link, "socket:[") || !strings.HasSuffix(link, "]") {
+		return 0, false
+	}
+
+	inode, err := strconv.ParseUint(link[len("socket:["):len(link)-1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return inode, true
+}
+
+// readProcProcess reads the real UID, name and command line of a process.
+// UID is -1 when it cannot be determined.
+func readProcProcess(root string, pid int) procProcess {
+	p := procProcess{PID: pid, UID: -1}
+	dir := filepath.Join(root, strconv.Itoa(pid))
+
+	if status, err := os.ReadFile(filepath.Join(dir, "status")); err == nil {
+		for _, line := range strings.Split(string(status), "\n") {
+			if fields := strings.Fields(line); len(fields) > 1 && fields[0] == "Uid:" {
+				p.UID, _ = strconv.Atoi(fields[1])
+				break
+			}
+		}
+	}
+
+	if comm, err := os.ReadFile(filepath.Join(dir, "comm")); err == nil {
+		p.Name = strings.TrimSpace(string(comm))
+	}
+
+	if cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")); err == nil {
+		p.Cmdline = strings.TrimSpace(strings.ReplaceAll(strings.TrimRight(string(cmdline), "\x00"), "\x00", " "))
+	}
+
+	p.StartTime, _ = procStartTime(root, pid)
+
+	return p
+}
+
+// procStartTime reads the start time of a process from field 22 of its stat file
+func procStartTime(root string, pid int) (uint64, bool) {
+	stat, err := os.ReadFile(filepath.Join(root, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, false
+	}
+
+	// Fields are counted from the state, which follows the parenthesised command name
+	i := strings.LastIndexByte(string(stat), ')')
+	if i < 0 {
+		return 0, false
+	}
+	fields := strings.Fields(string(stat[i+1:]))
+	if len(fields) < 20 {
+		return 0, false
+	}
+
+	start, err := strconv.ParseUint(fields[19], 10, 64)
+	return start, err == nil
+}
+
+// procExited reports whether the process is gone or only left as a zombie
+func procExited(root string, pid int) bool {
+	stat, err := os.ReadFile(filepath.Join(root, strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return true
+	}
+
+	// The state follows the parenthesised command name, which may itself contain spaces
+	i := strings.LastIndexByte(string(stat), ')')
+	if i < 0 || i+2 >= len(stat) {
+		return false
+	}
+
+	state := stat[i+2]
+	return state == 'Z' || state == 'X'
+}
@@ -0,0 +1,230 @@
+package tools
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const procNetTCPFixture = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 0100007F:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 12345 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:1F90 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 12346 1 0000000000000000 20 4 30 10 -1
+   2: garbage
+`
+
+const procNetTCP6Fixture = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000001000000:0016 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 22222 1 0000000000000000 100 0 0 10 0
+   1: 0000000000000000FFFF00000100007F:01BB 0000000000000000FFFF00000A00000A:C350 06 00000000:00000000 03:00000ED8 00000000     0        0 0 3 0000000000000000
+`
+
+// writeProcFixture builds a minimal proc tree with two processes, one of
+// them a zombie, holding sockets open
+func writeProcFixture(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	files := map[string]string{
+		"net/tcp":          procNetTCPFixture,
+		"net/tcp6":         procNetTCP6Fixture,
+		"100/status":       "Name:\tserver\nState:\tS (sleeping)\nUid:\t1000\t1000\t1000\t1000\n",
+		"100/comm":         "server\n",
+		"100/cmdline":      "/usr/bin/server\x00--port\x008080\x00",
+		"100/stat":         "100 (my server) S 1 100 100 0 -1 4194560 0 0 0 0 0 0 0 0 20 0 1 0 123456 1000 10",
+		"200/status":       "Name:\tsshd\nUid:\t0\t0\t0\t0\n",
+		"200/comm":         "sshd\n",
+		"200/stat":         "200 (sshd) Z 1 200 200 0 -1",
+		"self/status":      "Uid:\t0\n",
+		"not-a-pid/status": "Uid:\t0\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	links := map[string]string{
+		"100/fd/0": "/dev/null",
+		"100/fd/3": "socket:[12345]",
+		"100/fd/4": "socket:[12346]",
+		"200/fd/3": "socket:[22222]",
+		"200/fd/4": "socket:[12345]",
+		"200/fd/5": "pipe:[999]",
+	}
+	for name, target := range links {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Skipf("symlinks are not available: %v", err)
+		}
+	}
+
+	return root
+}
+
+func TestReadProcNet(t *testing.T) {
+	root := writeProcFixture(t)
+
+	tests := []struct {
+		proto string
+		want  []procSocket
+	}{
+		{
+			proto: "tcp",
+			want: []procSocket{
+				{Protocol: "tcp", LocalIP: net.IPv4(127, 0, 0, 1), LocalPort: 8080, RemoteIP: net.IPv4zero, RemotePort: 0, State: "LISTEN", UID: 1000, Inode: 12345},
+				{Protocol: "tcp", LocalIP: net.IPv4(127, 0, 0, 1), LocalPort: 8080, RemoteIP: net.IPv4(127, 0, 0, 1), RemotePort: 54321, State: "ESTABLISHED", UID: 1000, Inode: 12346},
+			},
+		},
+		{
+			proto: "tcp6",
+			want: []procSocket{
+				{Protocol: "tcp6", LocalIP: net.IPv6loopback, LocalPort: 22, RemoteIP: net.IPv6unspecified, RemotePort: 0, State: "LISTEN", UID: 0, Inode: 22222},
+				{Protocol: "tcp6", LocalIP: net.IPv4(127, 0, 0, 1), LocalPort: 443, RemoteIP: net.IPv4(10, 0, 0, 10), RemotePort: 50000, State: "TIME_WAIT", UID: 0, Inode: 0},
+			},
+		},
+		{proto: "udp6"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.proto, func(t *testing.T) {
+			got, err := readProcNet(root, tt.proto)
+			if err != nil {
+				t.Fatalf("readProcNet: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d sockets, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i, s := range got {
+				w := tt.want[i]
+				if s.Protocol != w.Protocol || !s.LocalIP.Equal(w.LocalIP) || s.LocalPort != w.LocalPort ||
+					!s.RemoteIP.Equal(w.RemoteIP) || s.RemotePort != w.RemotePort || s.State != w.State ||
+					s.UID != w.UID || s.Inode != w.Inode {
+					t.Errorf("socket %d = %+v, want %+v", i, s, w)
+				}
+			}
+		})
+	}
+}
+
+func TestParseProcNetAddr(t *testing.T) {
+	tests := []struct {
+		in      string
+		ip      string
+		port    int
+		wantErr bool
+	}{
+		{in: "0100007F:0050", ip: "127.0.0.1", port: 80},
+		{in: "0101A8C0:FFFF", ip: "192.168.1.1", port: 65535},
+		{in: "B80D0120000000000000000001000000:0035", ip: "2001:db8::1", port: 53},
+		{in: "0100007F", wantErr: true},
+		{in: "0100007:0050", wantErr: true},
+		{in: "0100007F0100:0050", wantErr: true},
+		{in: "0100007F:10000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		ip, port, err := parseProcNetAddr(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseProcNetAddr(%q) = %s:%d, want an error", tt.in, ip, port)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseProcNetAddr(%q): %v", tt.in, err)
+			continue
+		}
+		if !ip.Equal(net.ParseIP(tt.ip)) || port != tt.port {
+			t.Errorf("parseProcNetAddr(%q) = %s:%d, want %s:%d", tt.in, ip, port, tt.ip, tt.port)
+		}
+	}
+}
+
+func TestParseSocketLink(t *testing.T) {
+	tests := []struct {
+		link  string
+		inode uint64
+		ok    bool
+	}{
+		{link: "socket:[12345]", inode: 12345, ok: true},
+		{link: "socket:[]"},
+		{link: "socket:[abc]"},
+		{link: "pipe:[12345]"},
+		{link: "/dev/null"},
+	}
+
+	for _, tt := range tests {
+		inode, ok := parseSocketLink(tt.link)
+		if inode != tt.inode || ok != tt.ok {
+			t.Errorf("parseSocketLink(%q) = %d, %v, want %d, %v", tt.link, inode, ok, tt.inode, tt.ok)
+		}
+	}
+}
+
+func TestSocketOwners(t *testing.T) {
+	root := writeProcFixture(t)
+
+	owners, err := socketOwners(root)
+	if err != nil {
+		t.Fatalf("socketOwners: %v", err)
+	}
+
+	want := map[uint64]int{12346: 100, 22222: 200}
+	for inode, pid := range want {
+		if owners[inode] != pid {
+			t.Errorf("owner of inode %d = %d, want %d", inode, owners[inode], pid)
+		}
+	}
+	// A socket shared by two processes belongs to one of them
+	if pid := owners[12345]; pid != 100 && pid != 200 {
+		t.Errorf("owner of shared inode 12345 = %d, want 100 or 200", pid)
+	}
+	if len(owners) != 3 {
+		t.Errorf("got %d owners, want 3: %v", len(owners), owners)
+	}
+}
+
+func TestReadProcProcess(t *testing.T) {
+	root := writeProcFixture(t)
+
+	tests := []struct {
+		pid  int
+		want procProcess
+	}{
+		{pid: 100, want: procProcess{PID: 100, UID: 1000, Name: "server", Cmdline: "/usr/bin/server --port 8080", StartTime: 123456}},
+		{pid: 200, want: procProcess{PID: 200, UID: 0, Name: "sshd"}},
+		{pid: 300, want: procProcess{PID: 300, UID: -1}},
+	}
+
+	for _, tt := range tests {
+		if got := readProcProcess(root, tt.pid); got != tt.want {
+			t.Errorf("readProcProcess(%d) = %+v, want %+v", tt.pid, got, tt.want)
+		}
+	}
+}
+
+func TestProcExited(t *testing.T) {
+	root := writeProcFixture(t)
+
+	tests := []struct {
+		pid  int
+		want bool
+	}{
+		{pid: 100, want: false},
+		{pid: 200, want: true},
+		{pid: 300, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := procExited(root, tt.pid); got != tt.want {
+			t.Errorf("procExited(%d) = %v, want %v", tt.pid, got, tt.want)
+		}
+	}
+}
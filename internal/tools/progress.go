@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// progressInterval limits how often progress notifications are sent
+const progressInterval = 250 * time.Millisecond
+
+// progressReporter sends throttled progress notifications for a tool call.
+// It does nothing when the client did not ask for progress.
+type progressReporter struct {
+	ctx     context.Context
+	session *mcp.ServerSession
+	token   any
+
+	mu       sync.Mutex
+	progress float64
+	total    float64
+	lastSent time.Time
+}
+
+// newProgressReporter returns a reporter for the progress token of req
+func newProgressReporter(ctx context.Context, req *mcp.CallToolRequest) *progressReporter {
+	p := &progressReporter{ctx: ctx}
+	if req != nil && req.Session != nil && req.Params != nil {
+		p.session = req.Session
+		p.token = req.Params.GetProgressToken()
+	}
+	return p
+}
+
+// setTotal changes the expected total and sends a notification with message
+func (p *progressReporter) setTotal(total float64, message string) {
+	p.mu.Lock()
+	p.total = total
+	p.mu.Unlock()
+	p.send(message, true)
+}
+
+// add advances the progress by n, sending a notification if enough time has passed
+func (p *progressReporter) add(n float64, message string) {
+	p.mu.Lock()
+	p.progress += n
+	p.mu.Unlock()
+	p.send(message, false)
+}
+
+func (p *progressReporter) send(message string, force bool) {
+	if p.token == nil {
+		return
+	}
+
+	p.mu.Lock()
+	now := time.Now()
+	if !force && now.Sub(p.lastSent) < progressInterval {
+		p.mu.Unlock()
+		return
+	}
+	p.lastSent = now
+	params := &mcp.ProgressNotificationParams{ProgressToken: p.token, Message: message, Progress: p.progress, Total: p.total}
+	p.mu.Unlock()
+
+	// Progress is best effort; a failed notification must not fail the tool
+	_ = p.session.NotifyProgress(p.ctx, params)
+}
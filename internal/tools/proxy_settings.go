@@ -0,0 +1,415 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// proxyEnvNames are the proxy environment variables reported, in both casings
+var proxyEnvNames = []string{
+	"HTTP_PROXY", "http_proxy",
+	"HTTPS_PROXY", "https_proxy",
+	"NO_PROXY", "no_proxy",
+	"ALL_PROXY", "all_proxy",
+	"npm_config_proxy", "npm_config_https_proxy", "npm_config_noproxy",
+}
+
+type proxySettingsInput struct {
+	URL        string `json:"url,omitempty" jsonschema:"URL to evaluate: which proxy Go's http.ProxyFromEnvironment would use and whether NO_PROXY excludes it"`
+	ProjectDir string `json:"project_dir,omitempty" jsonschema:"Project directory whose .npmrc should also be inspected"`
+}
+
+type proxySetting struct {
+	Source string `json:"source" jsonschema:"Environment variable or configuration file the setting comes from"`
+	Key    string `json:"key" jsonschema:"Setting name"`
+	Value  string `json:"value" jsonschema:"Setting value, with passwords redacted"`
+}
+
+type proxyEvaluation struct {
+	URL               string `json:"url" jsonschema:"URL that was evaluated"`
+	Proxy             string `json:"proxy,omitempty" jsonschema:"Proxy that would be used, with passwords redacted"`
+	Direct            bool   `json:"direct" jsonschema:"Whether the request would bypass any proxy"`
+	ExcludedByNoProxy bool   `json:"excluded_by_no_proxy" jsonschema:"Whether a proxy is configured for the scheme but NO_PROXY excludes the host"`
+	NoProxyMatch      string `json:"no_proxy_match,omitempty" jsonschema:"NO_PROXY entry that matched the host"`
+	Reason            string `json:"reason" jsonschema:"Explanation of the decision"`
+}
+
+type proxyIssue struct {
+	Severity string `json:"severity" jsonschema:"Severity of the issue (warning or info)"`
+	Message  string `json:"message" jsonschema:"Description of the issue"`
+}
+
+type proxySettingsOutput struct {
+	Environment []proxySetting   `json:"environment" jsonschema:"Proxy related environment variables that are set"`
+	Git         []proxySetting   `json:"git" jsonschema:"Proxy settings from git configuration files"`
+	Npm         []proxySetting   `json:"npm" jsonschema:"Proxy settings from npm configuration files"`
+	Evaluation  *proxyEvaluation `json:"evaluation,omitempty" jsonschema:"Proxy decision for the given URL"`
+	Issues      []proxyIssue     `json:"issues" jsonschema:"Detected misconfigurations"`
+}
+
+// redactProxy hides the password of a proxy URL, including values without a
+// scheme such as "user:secret@proxy:8080"
+func redactProxy(value string) string {
+	raw := value
+	if !strings.Contains(value, "://") {
+		raw = "http://" + value
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		// Don't echo what may be credentials in a value we can't parse
+		if i := strings.LastIndex(value, "@"); i >= 0 {
+			return "xxxxx@" + value[i+1:]
+		}
+		return value
+	}
+	if u.User == nil {
+		return value
+	}
+
+	redacted := u.Redacted()
+	if raw != value {
+		redacted = strings.TrimPrefix(redacted, "http://")
+	}
+	return redacted
+}
+
+// getenvAny returns the first non-empty value of the given variables
+func getenvAny(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// gitConfigFiles returns the global and system git configuration files
+func gitConfigFiles() []string {
+	var files []string
+
+	if home, err := os.UserHomeDir(); err == nil {
+		files = append(files, filepath.Join(home, ".gitconfig"))
+		xdg := os.Getenv("XDG_CONFIG_HOME")
+		if xdg == "" {
+			xdg = filepath.Join(home, ".config")
+		}
+		files = append(files, filepath.Join(xdg, "git", "config"))
+	}
+
+	return append(files, "/etc/gitconfig")
+}
+
+// readGitProxySettings extracts http.proxy, https.proxy and http.<url>.proxy settings from a git config file
+func readGitProxySettings(path string) []proxySetting {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var settings []proxySetting
+	section := ""
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			// [http "https://example.com"] becomes http.https://example.com
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if base, sub, ok := strings.Cut(name, " "); ok {
+				name = strings.ToLower(base) + "." + strings.Trim(strings.TrimSpace(sub), `"`)
+			} else {
+				name = strings.ToLower(name)
+			}
+			section = name
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(key), "proxy") {
+			continue
+		}
+		if section != "http" && section != "https" && !strings.HasPrefix(section, "http.") {
+			continue
+		}
+
+		settings = append(settings, proxySetting{
+			Source: path,
+			Key:    section + ".proxy",
+			Value:  redactProxy(strings.Trim(strings.TrimSpace(value), `"`)),
+		})
+	}
+
+	return settings
+}
+
+// readNpmProxySettings extracts proxy settings from an .npmrc file
+func readNpmProxySettings(path string) []proxySetting {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var settings []proxySetting
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		switch key {
+		case "proxy", "http-proxy", "https-proxy", "noproxy", "no-proxy":
+			settings = append(settings, proxySetting{
+				Source: path,
+				Key:    key,
+				Value:  redactProxy(strings.Trim(strings.TrimSpace(value), `"`)),
+			})
+		}
+	}
+
+	return settings
+}
+
+// matchNoProxy returns the NO_PROXY entry matching host and port, following
+// the rules of Go's http.ProxyFromEnvironment
+func matchNoProxy(noProxy, host, port string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	ip := net.ParseIP(host)
+
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return entry
+		}
+
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && ipNet.Contains(ip) {
+				return entry
+			}
+			continue
+		}
+
+		pattern, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			pattern, entryPort = h, p
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+
+		if entryIP := net.ParseIP(pattern); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return entry
+			}
+			continue
+		}
+
+		pattern = strings.TrimPrefix(pattern, "*")
+		if strings.HasPrefix(pattern, ".") {
+			if strings.HasSuffix(host, pattern) || host == pattern[1:] {
+				return entry
+			}
+			continue
+		}
+		if host == pattern || strings.HasSuffix(host, "."+pattern) {
+			return entry
+		}
+	}
+
+	return ""
+}
+
+// evaluateProxy reports which proxy would be used for rawURL
+func evaluateProxy(rawURL string) (*proxyEvaluation, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid URL '%s'", rawURL)
+	}
+
+	eval := &proxyEvaluation{URL: rawURL}
+
+	proxyURL, err := http.ProxyFromEnvironment(&http.Request{URL: u})
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy configuration: %w", err)
+	}
+
+	configured := ""
+	switch u.Scheme {
+	case "https":
+		configured = getenvAny("HTTPS_PROXY", "https_proxy")
+	case "http":
+		configured = getenvAny("HTTP_PROXY", "http_proxy")
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443"}[u.Scheme]
+	}
+
+	if proxyURL != nil {
+		eval.Proxy = proxyURL.Redacted()
+		eval.Reason = fmt.Sprintf("the %s proxy from the environment is used", strings.ToUpper(u.Scheme))
+		return eval, nil
+	}
+
+	eval.Direct = true
+
+	switch host := u.Hostname(); {
+	case configured == "":
+		eval.Reason = fmt.Sprintf("no proxy is configured for %s URLs", u.Scheme)
+	case host == "localhost" || net.ParseIP(host) != nil && net.ParseIP(host).IsLoopback():
+		eval.Reason = "requests to localhost and loopback addresses are never proxied by Go"
+	default:
+		eval.ExcludedByNoProxy = true
+		eval.NoProxyMatch = matchNoProxy(getenvAny("NO_PROXY", "no_proxy"), host, port)
+		eval.Reason = "the host is excluded by NO_PROXY"
+	}
+
+	return eval, nil
+}
+
+// proxyIssues flags common proxy misconfigurations
+func proxyIssues(git []proxySetting) []proxyIssue {
+	issues := []proxyIssue{}
+	warn := func(format string, args ...any) {
+		issues = append(issues, proxyIssue{Severity: "warning", Message: fmt.Sprintf(format, args...)})
+	}
+	info := func(format string, args ...any) {
+		issues = append(issues, proxyIssue{Severity: "info", Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "ALL_PROXY"} {
+		upper, lower := os.Getenv(name), os.Getenv(strings.ToLower(name))
+		if upper != "" && lower != "" && upper != lower {
+			warn("%s and %s differ; tools disagree on which one wins (Go and curl prefer different casings)", name, strings.ToLower(name))
+		}
+	}
+
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY"} {
+		value := getenvAny(name, strings.ToLower(name))
+		if value == "" {
+			continue
+		}
+
+		u, err := url.Parse(value)
+		if err != nil || !strings.Contains(value, "://") {
+			redacted := redactProxy(value)
+			warn("%s value '%s' has no scheme; Go assumes http:// but many tools reject it, use e.g. 'http://%s'", name, redacted, redacted)
+			continue
+		}
+		if u.User != nil {
+			if _, hasPassword := u.User.Password(); hasPassword {
+				info("%s contains a plaintext password; it is visible to every child process", name)
+			}
+		}
+		if name == "HTTPS_PROXY" && u.Scheme == "https" {
+			info("HTTPS_PROXY uses the https:// scheme; most corporate proxies expect http:// even for HTTPS traffic")
+		}
+		if u.Port() == "" {
+			info("%s has no port; the default port for %s will be used", name, u.Scheme)
+		}
+	}
+
+	httpProxy := getenvAny("HTTP_PROXY", "http_proxy")
+	httpsProxy := getenvAny("HTTPS_PROXY", "https_proxy")
+	if httpProxy != "" && httpsProxy == "" {
+		warn("HTTP_PROXY is set but HTTPS_PROXY is not; HTTPS requests will not use the proxy")
+	}
+
+	noProxy := getenvAny("NO_PROXY", "no_proxy")
+	if noProxy != "" {
+		for _, entry := range strings.Split(noProxy, ",") {
+			trimmed := strings.TrimSpace(entry)
+			if trimmed != entry {
+				info("NO_PROXY entry '%s' contains spaces; some tools do not trim them", trimmed)
+			}
+			if strings.HasPrefix(trimmed, "*.") {
+				warn("NO_PROXY entry '%s' uses a wildcard that curl and many tools do not support, use '%s' instead", trimmed, trimmed[1:])
+			}
+		}
+	}
+
+	if (httpProxy != "" || httpsProxy != "") && matchNoProxy(noProxy, "localhost", "") == "" {
+		info("NO_PROXY does not contain 'localhost'; tools other than Go may send local requests through the proxy")
+	}
+
+	for _, s := range git {
+		envProxy := httpProxy
+		if strings.HasPrefix(s.Key, "https") {
+			envProxy = httpsProxy
+		}
+		if envProxy != "" && s.Value != redactProxy(envProxy) {
+			info("git %s (%s) differs from the environment proxy", s.Key, s.Value)
+		}
+	}
+
+	return issues
+}
+
+// ProxySettings reports the effective proxy configuration and flags common misconfigurations
+func ProxySettings(ctx context.Context, req *mcp.CallToolRequest, input proxySettingsInput) (*mcp.CallToolResult, *proxySettingsOutput, error) {
+	output := &proxySettingsOutput{
+		Environment: []proxySetting{},
+		Git:         []proxySetting{},
+		Npm:         []proxySetting{},
+	}
+
+	for _, name := range proxyEnvNames {
+		if v := os.Getenv(name); v != "" {
+			output.Environment = append(output.Environment, proxySetting{Source: "environment", Key: name, Value: redactProxy(v)})
+		}
+	}
+
+	for _, path := range gitConfigFiles() {
+		output.Git = append(output.Git, readGitProxySettings(path)...)
+	}
+
+	var npmrcs []string
+	if home, err := os.UserHomeDir(); err == nil {
+		npmrcs = append(npmrcs, filepath.Join(home, ".npmrc"))
+	}
+	if input.ProjectDir != "" {
+		npmrcs = append(npmrcs, filepath.Join(input.ProjectDir, ".npmrc"))
+	}
+	for _, path := range npmrcs {
+		output.Npm = append(output.Npm, readNpmProxySettings(path)...)
+	}
+
+	if input.URL != "" {
+		eval, err := evaluateProxy(input.URL)
+		if err != nil {
+			return nil, nil, err
+		}
+		output.Evaluation = eval
+	}
+
+	output.Issues = proxyIssues(output.Git)
+
+	return nil, output, nil
+}
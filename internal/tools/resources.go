@@ -0,0 +1,41 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// subscribableSchemes lists the resource URI schemes clients may subscribe to
+var subscribableSchemes = []string{timerURIPrefix, dirURIPrefix}
+
+// SubscribeResource accepts subscriptions to resources that send update
+// notifications, watching directories with dirs for dir:// resources
+func SubscribeResource(ctx context.Context, req *mcp.SubscribeRequest, dirs *DirWatchers) error {
+	uri := req.Params.URI
+	for _, scheme := range subscribableSchemes {
+		if !strings.HasPrefix(uri, scheme) {
+			continue
+		}
+		// Directories are only watched while someone is subscribed
+		if scheme == dirURIPrefix {
+			if dirs == nil {
+				return fmt.Errorf("directory watching is not enabled")
+			}
+			return dirs.subscribe(req.Session, uri)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("resource %s does not support subscriptions", uri)
+}
+
+// UnsubscribeResource removes a subscription added by SubscribeResource
+func UnsubscribeResource(ctx context.Context, req *mcp.UnsubscribeRequest, dirs *DirWatchers) error {
+	if strings.HasPrefix(req.Params.URI, dirURIPrefix) && dirs != nil {
+		dirs.unsubscribe(req.Session, req.Params.URI)
+	}
+	return nil
+}
@@ -0,0 +1,398 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxSplitSubnets limits how many subnets a split may produce
+const maxSplitSubnets = 1024
+
+type subnetCalcInput struct {
+	CIDR          string   `json:"cidr,omitempty" jsonschema:"Subnet in CIDR notation (e.g. '192.168.1.10/24', '2001:db8::/48') or an IP address combined with mask"`
+	Mask          string   `json:"mask,omitempty" jsonschema:"Netmask for an IP address given without prefix, e.g. '255.255.255.0' or '24'"`
+	Contains      []string `json:"contains,omitempty" jsonschema:"Addresses to test for membership in the subnet"`
+	Split         int      `json:"split,omitempty" jsonschema:"Split the subnet into at least this many equally sized subnets"`
+	Aggregate     []string `json:"aggregate,omitempty" jsonschema:"CIDRs to aggregate into the smallest list of covering prefixes"`
+	CheckOverlaps bool     `json:"check_overlaps,omitempty" jsonschema:"Check whether the subnet overlaps the subnets of this machine's network interfaces"`
+}
+
+type subnetInfo struct {
+	CIDR           string `json:"cidr" jsonschema:"Network in CIDR notation"`
+	Version        int    `json:"version" jsonschema:"IP version (4 or 6)"`
+	Address        string `json:"address" jsonschema:"Address the subnet was derived from"`
+	PrefixLength   int    `json:"prefix_length" jsonschema:"Prefix length in bits"`
+	Netmask        string `json:"netmask" jsonschema:"Netmask"`
+	WildcardMask   string `json:"wildcard_mask" jsonschema:"Wildcard (inverse) mask"`
+	Network        string `json:"network" jsonschema:"Network address"`
+	Broadcast      string `json:"broadcast,omitempty" jsonschema:"Broadcast address (IPv4 only)"`
+	FirstHost      string `json:"first_host" jsonschema:"First usable host address"`
+	LastHost       string `json:"last_host" jsonschema:"Last usable host address"`
+	TotalAddresses string `json:"total_addresses" jsonschema:"Number of addresses in the subnet (as a string, IPv6 counts exceed 64 bits)"`
+	UsableHosts    string `json:"usable_hosts" jsonschema:"Number of usable host addresses (as a string)"`
+	Expanded       string `json:"expanded,omitempty" jsonschema:"Fully expanded form of the IPv6 network address"`
+	Compressed     string `json:"compressed,omitempty" jsonschema:"Compressed form of the IPv6 network address"`
+}
+
+type subnetMembership struct {
+	Address string `json:"address" jsonschema:"Address that was tested"`
+	Inside  bool   `json:"inside" jsonschema:"Whether the address is inside the subnet"`
+}
+
+type interfaceOverlap struct {
+	Interface string `json:"interface" jsonschema:"Network interface name"`
+	Subnet    string `json:"subnet" jsonschema:"Subnet configured on the interface"`
+}
+
+type subnetCalcOutput struct {
+	Subnet     *subnetInfo        `json:"subnet,omitempty" jsonschema:"Details of the subnet"`
+	Contains   []subnetMembership `json:"contains,omitempty" jsonschema:"Membership of the tested addresses"`
+	Subnets    []string           `json:"subnets,omitempty" jsonschema:"Subnets produced by splitting"`
+	Aggregated []string           `json:"aggregated,omitempty" jsonschema:"Smallest list of prefixes covering exactly the aggregated CIDRs"`
+	Supernets  []string           `json:"supernets,omitempty" jsonschema:"Smallest single prefix covering all aggregated CIDRs, per IP version"`
+	Overlaps   []interfaceOverlap `json:"overlaps,omitempty" jsonschema:"Interface subnets overlapping the subnet"`
+}
+
+// parseSubnet parses a CIDR, or an address together with a dotted or numeric mask
+func parseSubnet(cidr, mask string) (netip.Prefix, error) {
+	cidr = strings.TrimSpace(cidr)
+
+	// Accept "192.168.1.10 255.255.255.0" in a single field
+	if fields := strings.Fields(cidr); len(fields) == 2 && mask == "" {
+		cidr, mask = fields[0], fields[1]
+	}
+
+	if strings.Contains(cidr, "/") {
+		if mask != "" {
+			return netip.Prefix{}, fmt.Errorf("mask cannot be combined with a CIDR")
+		}
+		p, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid CIDR '%s': %w", cidr, err)
+		}
+		return p, nil
+	}
+
+	addr, err := netip.ParseAddr(cidr)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address '%s': %w", cidr, err)
+	}
+	addr = addr.Unmap()
+
+	if mask == "" {
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	bits, err := parseMask(strings.TrimPrefix(strings.TrimSpace(mask), "/"), addr.BitLen())
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	return netip.PrefixFrom(addr, bits), nil
+}
+
+// parseMask returns the prefix length of a numeric or dotted netmask
+func parseMask(mask string, bitLen int) (int, error) {
+	if n, err := strconv.Atoi(mask); err == nil {
+		if n < 0 || n > bitLen {
+			return 0, fmt.Errorf("invalid prefix length %d", n)
+		}
+		return n, nil
+	}
+
+	m, err := netip.ParseAddr(mask)
+	if err != nil {
+		return 0, fmt.Errorf("invalid mask '%s'", mask)
+	}
+
+	ones, bits := net.IPMask(m.AsSlice()).Size()
+	if bits == 0 || bits != bitLen {
+		return 0, fmt.Errorf("invalid mask '%s': not a contiguous netmask for this address", mask)
+	}
+
+	return ones, nil
+}
+
+// addrToBig converts an address to an integer
+func addrToBig(a netip.Addr) *big.Int {
+	return new(big.Int).SetBytes(a.AsSlice())
+}
+
+// bigToAddr converts an integer back to an address of the given bit length
+func bigToAddr(n *big.Int, bitLen int) netip.Addr {
+	buf := make([]byte, bitLen/8)
+	n.FillBytes(buf)
+	a, _ := netip.AddrFromSlice(buf)
+	return a
+}
+
+// prefixMask returns the netmask of a prefix length as an address
+func prefixMask(bits, bitLen int) netip.Addr {
+	a, _ := netip.AddrFromSlice(net.CIDRMask(bits, bitLen))
+	return a
+}
+
+// lastAddr returns the highest address of a prefix
+func lastAddr(p netip.Prefix) netip.Addr {
+	bitLen := p.Addr().BitLen()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bitLen-p.Bits()))
+	last := new(big.Int).Add(addrToBig(p.Masked().Addr()), size)
+	return bigToAddr(last.Sub(last, big.NewInt(1)), bitLen)
+}
+
+// describeSubnet computes the details of a prefix
+func describeSubnet(p netip.Prefix) *subnetInfo {
+	network := p.Masked()
+	bitLen := p.Addr().BitLen()
+	mask := prefixMask(p.Bits(), bitLen)
+	wildcard := make([]byte, bitLen/8)
+	for i, b := range mask.AsSlice() {
+		wildcard[i] = ^b
+	}
+	wildcardAddr, _ := netip.AddrFromSlice(wildcard)
+
+	first, last := network.Addr(), lastAddr(network)
+	total := new(big.Int).Lsh(big.NewInt(1), uint(bitLen-p.Bits()))
+	usable := new(big.Int).Set(total)
+
+	info := &subnetInfo{
+		CIDR:         network.String(),
+		Address:      p.Addr().String(),
+		PrefixLength: p.Bits(),
+		Netmask:      mask.String(),
+		WildcardMask: wildcardAddr.String(),
+		Network:      network.Addr().String(),
+	}
+
+	if p.Addr().Is4() {
+		info.Version = 4
+		info.Broadcast = last.String()
+		// /31 and /32 have no network or broadcast address (RFC 3021)
+		if p.Bits() < 31 {
+			first, last = first.Next(), last.Prev()
+			usable.Sub(usable, big.NewInt(2))
+		}
+	} else {
+		info.Version = 6
+		info.Expanded = network.Addr().StringExpanded()
+		info.Compressed = network.Addr().String()
+	}
+
+	info.FirstHost = first.String()
+	info.LastHost = last.String()
+	info.TotalAddresses = total.String()
+	info.UsableHosts = usable.String()
+
+	return info
+}
+
+// splitSubnet splits a prefix into the smallest power of two of subnets that is at least n
+func splitSubnet(p netip.Prefix, n int) ([]string, error) {
+	if n > maxSplitSubnets {
+		return nil, fmt.Errorf("cannot split into more than %d subnets", maxSplitSubnets)
+	}
+
+	extra := 0
+	for 1<<extra < n {
+		extra++
+	}
+
+	bitLen := p.Addr().BitLen()
+	bits := p.Bits() + extra
+	if bits > bitLen {
+		return nil, fmt.Errorf("%s is too small to be split into %d subnets", p.Masked(), n)
+	}
+
+	step := new(big.Int).Lsh(big.NewInt(1), uint(bitLen-bits))
+	cur := addrToBig(p.Masked().Addr())
+
+	subnets := make([]string, 0, 1<<extra)
+	for i := 0; i < 1<<extra; i++ {
+		subnets = append(subnets, netip.PrefixFrom(bigToAddr(cur, bitLen), bits).String())
+		cur.Add(cur, step)
+	}
+
+	return subnets, nil
+}
+
+// aggregatePrefixes merges prefixes into the smallest list covering exactly the same addresses
+func aggregatePrefixes(prefixes []netip.Prefix) []netip.Prefix {
+	sort.Slice(prefixes, func(i, j int) bool {
+		if c := prefixes[i].Addr().Compare(prefixes[j].Addr()); c != 0 {
+			return c < 0
+		}
+		return prefixes[i].Bits() < prefixes[j].Bits()
+	})
+
+	// Drop prefixes contained in an earlier one
+	var result []netip.Prefix
+	for _, p := range prefixes {
+		if len(result) > 0 {
+			last := result[len(result)-1]
+			if last.Bits() <= p.Bits() && last.Contains(p.Addr()) {
+				continue
+			}
+		}
+		result = append(result, p)
+	}
+
+	// Repeatedly merge adjacent sibling prefixes into their parent
+	for merged := true; merged; {
+		merged = false
+		var next []netip.Prefix
+		for i := 0; i < len(result); i++ {
+			p := result[i]
+			if i+1 < len(result) && p.Bits() > 0 && p.Bits() == result[i+1].Bits() {
+				parent, _ := p.Addr().Prefix(p.Bits() - 1)
+				if parent.Addr() == p.Addr() && parent.Contains(result[i+1].Addr()) {
+					next = append(next, parent)
+					i++
+					merged = true
+					continue
+				}
+			}
+			next = append(next, p)
+		}
+		result = next
+	}
+
+	return result
+}
+
+// coveringPrefix returns the smallest prefix containing all prefixes, which must share an IP version
+func coveringPrefix(prefixes []netip.Prefix) netip.Prefix {
+	lo, hi := prefixes[0].Addr(), lastAddr(prefixes[0])
+	for _, p := range prefixes[1:] {
+		if p.Addr().Less(lo) {
+			lo = p.Addr()
+		}
+		if l := lastAddr(p); hi.Less(l) {
+			hi = l
+		}
+	}
+
+	for bits := lo.BitLen(); bits >= 0; bits-- {
+		p, _ := lo.Prefix(bits)
+		if p.Contains(hi) {
+			return p
+		}
+	}
+
+	return netip.Prefix{}
+}
+
+// interfaceSubnets returns the subnets configured on the machine's interfaces
+func interfaceSubnets() ([]interfaceOverlap, []netip.Prefix, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get network interfaces: %w", err)
+	}
+
+	var names []interfaceOverlap
+	var prefixes []netip.Prefix
+
+	for _, iface := range ifaces {
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			a, ok := netip.AddrFromSlice(ipNet.IP)
+			if !ok {
+				continue
+			}
+			ones, _ := ipNet.Mask.Size()
+			p := netip.PrefixFrom(a.Unmap(), ones).Masked()
+			names = append(names, interfaceOverlap{Interface: iface.Name, Subnet: p.String()})
+			prefixes = append(prefixes, p)
+		}
+	}
+
+	return names, prefixes, nil
+}
+
+// SubnetCalc calculates subnet details, splits and aggregates prefixes and detects overlaps
+func SubnetCalc(ctx context.Context, req *mcp.CallToolRequest, input subnetCalcInput) (*mcp.CallToolResult, *subnetCalcOutput, error) {
+	if input.CIDR == "" && len(input.Aggregate) == 0 {
+		return nil, nil, fmt.Errorf("either cidr or aggregate must be given")
+	}
+
+	output := &subnetCalcOutput{}
+
+	if input.CIDR != "" {
+		p, err := parseSubnet(input.CIDR, input.Mask)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		output.Subnet = describeSubnet(p)
+		network := p.Masked()
+
+		for _, s := range input.Contains {
+			a, err := netip.ParseAddr(strings.TrimSpace(s))
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid IP address '%s': %w", s, err)
+			}
+			output.Contains = append(output.Contains, subnetMembership{Address: s, Inside: network.Contains(a.Unmap())})
+		}
+
+		if input.Split > 0 {
+			output.Subnets, err = splitSubnet(p, input.Split)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+
+		if input.CheckOverlaps {
+			names, prefixes, err := interfaceSubnets()
+			if err != nil {
+				return nil, nil, err
+			}
+			output.Overlaps = []interfaceOverlap{}
+			for i, q := range prefixes {
+				if q.Overlaps(network) {
+					output.Overlaps = append(output.Overlaps, names[i])
+				}
+			}
+		}
+	}
+
+	if len(input.Aggregate) > 0 {
+		var v4, v6 []netip.Prefix
+		for _, s := range input.Aggregate {
+			p, err := parseSubnet(s, "")
+			if err != nil {
+				return nil, nil, err
+			}
+			if p.Addr().Is4() {
+				v4 = append(v4, p.Masked())
+			} else {
+				v6 = append(v6, p.Masked())
+			}
+		}
+
+		for _, family := range [][]netip.Prefix{v4, v6} {
+			if len(family) == 0 {
+				continue
+			}
+			for _, p := range aggregatePrefixes(family) {
+				output.Aggregated = append(output.Aggregated, p.String())
+			}
+			output.Supernets = append(output.Supernets, coveringPrefix(family).String())
+		}
+	}
+
+	return nil, output, nil
+}
@@ -0,0 +1,190 @@
+package tools
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestParseSubnet(t *testing.T) {
+	tests := []struct {
+		cidr, mask string
+		want       string
+		wantErr    bool
+	}{
+		{cidr: "192.168.1.10/24", want: "192.168.1.10/24"},
+		{cidr: "192.168.1.10", mask: "255.255.255.0", want: "192.168.1.10/24"},
+		{cidr: "192.168.1.10 255.255.240.0", want: "192.168.1.10/20"},
+		{cidr: "10.1.2.3", mask: "/8", want: "10.1.2.3/8"},
+		{cidr: "10.1.2.3", want: "10.1.2.3/32"},
+		{cidr: "::ffff:10.1.2.3", mask: "16", want: "10.1.2.3/16"},
+		{cidr: "2001:db8::1/48", want: "2001:db8::1/48"},
+		{cidr: "2001:db8::1", mask: "ffff:ffff::", want: "2001:db8::1/32"},
+		{cidr: "10.0.0.0/8", mask: "255.0.0.0", wantErr: true},
+		{cidr: "10.0.0.0/33", wantErr: true},
+		{cidr: "10.0.0.1", mask: "255.0.255.0", wantErr: true},
+		{cidr: "10.0.0.1", mask: "33", wantErr: true},
+		{cidr: "10.0.0.1", mask: "ffff::", wantErr: true},
+		{cidr: "not-an-ip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseSubnet(tt.cidr, tt.mask)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSubnet(%q, %q) = %s, want an error", tt.cidr, tt.mask, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSubnet(%q, %q): %v", tt.cidr, tt.mask, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("parseSubnet(%q, %q) = %s, want %s", tt.cidr, tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeSubnet(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   subnetInfo
+	}{
+		{
+			prefix: "192.168.1.10/24",
+			want: subnetInfo{
+				CIDR: "192.168.1.0/24", Version: 4, Address: "192.168.1.10", PrefixLength: 24,
+				Netmask: "255.255.255.0", WildcardMask: "0.0.0.255", Network: "192.168.1.0", Broadcast: "192.168.1.255",
+				FirstHost: "192.168.1.1", LastHost: "192.168.1.254", TotalAddresses: "256", UsableHosts: "254",
+			},
+		},
+		{
+			prefix: "10.0.0.1/31",
+			want: subnetInfo{
+				CIDR: "10.0.0.0/31", Version: 4, Address: "10.0.0.1", PrefixLength: 31,
+				Netmask: "255.255.255.254", WildcardMask: "0.0.0.1", Network: "10.0.0.0", Broadcast: "10.0.0.1",
+				FirstHost: "10.0.0.0", LastHost: "10.0.0.1", TotalAddresses: "2", UsableHosts: "2",
+			},
+		},
+		{
+			prefix: "10.0.0.7/32",
+			want: subnetInfo{
+				CIDR: "10.0.0.7/32", Version: 4, Address: "10.0.0.7", PrefixLength: 32,
+				Netmask: "255.255.255.255", WildcardMask: "0.0.0.0", Network: "10.0.0.7", Broadcast: "10.0.0.7",
+				FirstHost: "10.0.0.7", LastHost: "10.0.0.7", TotalAddresses: "1", UsableHosts: "1",
+			},
+		},
+		{
+			prefix: "2001:db8:abcd::1/48",
+			want: subnetInfo{
+				CIDR: "2001:db8:abcd::/48", Version: 6, Address: "2001:db8:abcd::1", PrefixLength: 48,
+				Netmask: "ffff:ffff:ffff::", WildcardMask: "::ffff:ffff:ffff:ffff:ffff", Network: "2001:db8:abcd::",
+				FirstHost: "2001:db8:abcd::", LastHost: "2001:db8:abcd:ffff:ffff:ffff:ffff:ffff",
+				TotalAddresses: "1208925819614629174706176", UsableHosts: "1208925819614629174706176",
+				Expanded: "2001:0db8:abcd:0000:0000:0000:0000:0000", Compressed: "2001:db8:abcd::",
+			},
+		},
+		{
+			prefix: "::/0",
+			want: subnetInfo{
+				CIDR: "::/0", Version: 6, Address: "::", PrefixLength: 0,
+				Netmask: "::", WildcardMask: "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", Network: "::",
+				FirstHost: "::", LastHost: "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff",
+				TotalAddresses: "340282366920938463463374607431768211456", UsableHosts: "340282366920938463463374607431768211456",
+				Expanded: "0000:0000:0000:0000:0000:0000:0000:0000", Compressed: "::",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got := describeSubnet(netip.MustParsePrefix(tt.prefix))
+		if *got != tt.want {
+			t.Errorf("describeSubnet(%s) =\n%+v\nwant\n%+v", tt.prefix, *got, tt.want)
+		}
+	}
+}
+
+func TestSplitSubnet(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		n       int
+		want    string
+		wantErr bool
+	}{
+		{prefix: "192.168.0.0/24", n: 4, want: "192.168.0.0/26 192.168.0.64/26 192.168.0.128/26 192.168.0.192/26"},
+		{prefix: "192.168.0.77/24", n: 3, want: "192.168.0.0/26 192.168.0.64/26 192.168.0.128/26 192.168.0.192/26"},
+		{prefix: "10.0.0.0/8", n: 1, want: "10.0.0.0/8"},
+		{prefix: "2001:db8::/32", n: 2, want: "2001:db8::/33 2001:db8:8000::/33"},
+		{prefix: "10.0.0.0/31", n: 4, wantErr: true},
+		{prefix: "10.0.0.0/8", n: maxSplitSubnets + 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := splitSubnet(netip.MustParsePrefix(tt.prefix), tt.n)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("splitSubnet(%s, %d) = %v, want an error", tt.prefix, tt.n, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("splitSubnet(%s, %d): %v", tt.prefix, tt.n, err)
+			continue
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("splitSubnet(%s, %d) = %v, want %s", tt.prefix, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestAggregatePrefixes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "10.0.0.0/25 10.0.0.128/25", want: "10.0.0.0/24"},
+		{in: "10.0.1.0/24 10.0.0.0/24 10.0.2.0/24 10.0.3.0/24", want: "10.0.0.0/22"},
+		{in: "10.0.1.0/24 10.0.2.0/24", want: "10.0.1.0/24 10.0.2.0/24"},
+		{in: "10.0.0.0/16 10.0.5.0/24 10.0.0.0/16", want: "10.0.0.0/16"},
+		{in: "10.0.0.0/24 10.0.1.0/25 10.0.1.128/25", want: "10.0.0.0/23"},
+		{in: "2001:db8::/33 2001:db8:8000::/33 10.0.0.0/8", want: "10.0.0.0/8 2001:db8::/32"},
+	}
+
+	for _, tt := range tests {
+		var prefixes []netip.Prefix
+		for _, s := range strings.Fields(tt.in) {
+			prefixes = append(prefixes, netip.MustParsePrefix(s))
+		}
+
+		var got []string
+		for _, p := range aggregatePrefixes(prefixes) {
+			got = append(got, p.String())
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("aggregatePrefixes(%s) = %v, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCoveringPrefix(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "10.0.1.0/24 10.0.2.0/24", want: "10.0.0.0/22"},
+		{in: "192.168.1.0/24", want: "192.168.1.0/24"},
+		{in: "10.0.0.0/8 192.168.0.0/16", want: "0.0.0.0/0"},
+		{in: "2001:db8::/48 2001:db8:1::/48", want: "2001:db8::/47"},
+	}
+
+	for _, tt := range tests {
+		var prefixes []netip.Prefix
+		for _, s := range strings.Fields(tt.in) {
+			prefixes = append(prefixes, netip.MustParsePrefix(s))
+		}
+		if got := coveringPrefix(prefixes); got.String() != tt.want {
+			t.Errorf("coveringPrefix(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// zoneAbbreviations maps common zone abbreviations to their fixed UTC offsets
+// in seconds. Abbreviations are ambiguous in general; these are the readings
+// most developers mean.
+var zoneAbbreviations = map[string]int{
+	"UTC": 0, "GMT": 0, "Z": 0, "UT": 0,
+	"PST": -8 * 3600, "PDT": -7 * 3600,
+	"MST": -7 * 3600, "MDT": -6 * 3600,
+	"CST": -6 * 3600, "CDT": -5 * 3600,
+	"EST": -5 * 3600, "EDT": -4 * 3600,
+	"AKST": -9 * 3600, "AKDT": -8 * 3600,
+	"HST": -10 * 3600,
+	"BST": 1 * 3600, "IST": 5*3600 + 1800,
+	"WET": 0, "WEST": 1 * 3600,
+	"CET": 1 * 3600, "CEST": 2 * 3600,
+	"EET": 2 * 3600, "EEST": 3 * 3600,
+	"MSK": 3 * 3600,
+	"JST": 9 * 3600, "KST": 9 * 3600,
+	"SGT": 8 * 3600, "HKT": 8 * 3600,
+	"AEST": 10 * 3600, "AEDT": 11 * 3600,
+	"ACST": 9*3600 + 1800, "ACDT": 10*3600 + 1800,
+	"AWST": 8 * 3600,
+	"NZST": 12 * 3600, "NZDT": 13 * 3600,
+}
+
+// ambiguousAbbreviations notes abbreviations with several common readings
+var ambiguousAbbreviations = map[string]string{
+	"CST": "CST was read as US Central Standard Time (UTC-06:00); China Standard Time is UTC+08:00",
+	"IST": "IST was read as India Standard Time (UTC+05:30); Irish and Israel Standard Time differ",
+	"BST": "BST was read as British Summer Time (UTC+01:00)",
+}
+
+// zonedLayouts are layouts carrying their own offset or zone
+var zonedLayouts = []struct{ name, layout string }{
+	{"RFC3339", time.RFC3339Nano},
+	{"RFC1123Z", time.RFC1123Z},
+	{"RFC1123", time.RFC1123},
+	{"RFC822Z", time.RFC822Z},
+	{"RFC822", time.RFC822},
+	{"RFC850", time.RFC850},
+	{"UnixDate", time.UnixDate},
+	{"RubyDate", time.RubyDate},
+	{"Common Log Format", "02/Jan/2006:15:04:05 -0700"},
+	{"Go default", "2006-01-02 15:04:05.999999999 -0700 MST"},
+	{"ISO 8601 with offset", "2006-01-02 15:04:05.999999999-07:00"},
+	{"ISO 8601 with offset", "2006-01-02T15:04:05.999999999-0700"},
+	{"ISO 8601 with offset", "2006-01-02 15:04:05.999999999 -0700"},
+}
+
+// naiveLayouts are layouts without zone information
+var naiveLayouts = []struct{ name, layout string }{
+	{"ISO 8601 local", "2006-01-02T15:04:05.999999999"},
+	{"ISO 8601 local", "2006-01-02T15:04"},
+	{"date time", "2006-01-02 15:04:05.999999999"},
+	{"date time", "2006-01-02 15:04"},
+	{"date time", "2006/01/02 15:04:05.999999999"},
+	{"date time", "2006/01/02 15:04"},
+	{"date", "2006-01-02"},
+	{"date", "2006/01/02"},
+	{"ANSIC", time.ANSIC},
+	{"RFC1123", "Mon, 02 Jan 2006 15:04:05"},
+	{"Common Log Format", "02/Jan/2006:15:04:05"},
+	{"syslog", time.Stamp},
+	{"US date time", "01/02/2006 15:04:05"},
+	{"US date time", "01/02/2006 3:04 PM"},
+	{"US date", "01/02/2006"},
+	{"long date", "January 2, 2006 15:04"},
+	{"long date", "January 2, 2006 3:04 PM"},
+	{"long date", "January 2, 2006"},
+	{"long date", "2 January 2006 15:04"},
+	{"long date", "2 January 2006"},
+}
+
+// compactLayouts are basic ISO 8601 forms made only of digits, which would
+// otherwise be read as Unix timestamps
+var compactLayouts = []struct{ name, layout string }{
+	{"compact date (YYYYMMDD)", "20060102"},
+	{"compact date time (YYYYMMDDhhmmss)", "20060102150405"},
+}
+
+var (
+	unixTimestampPattern = regexp.MustCompile(`^-?\d+(\.\d+)?$`)
+	trailingZonePattern  = regexp.MustCompile(`^(.*\S)\s+([A-Za-z][A-Za-z_]*(?:/[A-Za-z0-9_+-]+)*)$`)
+)
+
+type timeConvertInput struct {
+	Time         string   `json:"time" jsonschema:"Timestamp to convert: RFC3339, RFC1123, Unix seconds/ms/µs/ns, log formats or natural forms like '2025-03-01 14:00 PST' or '2025-03-01 14:00 Europe/Berlin'"`
+	FromTimezone string   `json:"from_timezone,omitempty" jsonschema:"IANA zone for timestamps without zone information, defaults to the server's local zone"`
+	ToTimezones  []string `json:"to_timezones,omitempty" jsonschema:"IANA zones to convert to, defaults to UTC and the server's local zone"`
+	Format       string   `json:"format,omitempty" jsonschema:"Output format for the converted times, as accepted by get_current_time. Defaults to rfc3339"`
+}
+
+type dstResolution struct {
+	Status      string   `json:"status" jsonschema:"'ambiguous' if the local time occurs twice, 'nonexistent' if it falls into a DST gap"`
+	Explanation string   `json:"explanation" jsonschema:"What happened and which instant was chosen"`
+	Candidates  []string `json:"candidates,omitempty" jsonschema:"All instants the local time can refer to"`
+}
+
+type timeConvertOutput struct {
+	Input          string         `json:"input" jsonschema:"Input timestamp"`
+	DetectedFormat string         `json:"detected_format" jsonschema:"Format the input was recognised as"`
+	SourceZone     string         `json:"source_zone" jsonschema:"Zone or offset the input was interpreted in"`
+	UTC            string         `json:"utc" jsonschema:"Instant in UTC (RFC3339)"`
+	Unix           int64          `json:"unix" jsonschema:"Unix time in seconds"`
+	UnixMillis     int64          `json:"unix_ms" jsonschema:"Unix time in milliseconds"`
+	DST            *dstResolution `json:"dst,omitempty" jsonschema:"Explanation for local times that are ambiguous or do not exist because of a DST transition"`
+	Notes          []string       `json:"notes,omitempty" jsonschema:"Notes about how the input was interpreted"`
+	Conversions    []zonedTime    `json:"conversions" jsonschema:"Time in each target zone"`
+}
+
+// parsedTimestamp is the result of parsing a timestamp
+type parsedTimestamp struct {
+	t      time.Time
+	format string
+	zone   string
+	dst    *dstResolution
+	notes  []string
+}
+
+// parseUnixTimestamp parses a Unix timestamp, guessing its unit from the number of digits
+func parseUnixTimestamp(s string) (time.Time, string, bool) {
+	intPart, fracPart, _ := strings.Cut(s, ".")
+	n, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil {
+		return time.Time{}, "", false
+	}
+
+	frac := 0.0
+	if fracPart != "" {
+		frac, _ = strconv.ParseFloat("0."+fracPart, 64)
+		if strings.HasPrefix(intPart, "-") {
+			frac = -frac
+		}
+	}
+
+	switch digits := len(strings.TrimPrefix(intPart, "-")); {
+	case digits <= 11:
+		return time.Unix(n, int64(math.Round(frac*1e9))).UTC(), "Unix seconds", true
+	case digits <= 14:
+		return time.UnixMilli(n).Add(time.Duration(frac * 1e6)).UTC(), "Unix milliseconds", true
+	case digits <= 17:
+		return time.UnixMicro(n).Add(time.Duration(frac * 1e3)).UTC(), "Unix microseconds", true
+	default:
+		return time.Unix(0, n).UTC(), "Unix nanoseconds", true
+	}
+}
+
+// parseCompactDate parses digits-only dates such as 20250301. Only plausible
+// years are accepted so small Unix timestamps keep their meaning.
+func parseCompactDate(s string) (time.Time, string, bool) {
+	for _, l := range compactLayouts {
+		if len(s) != len(l.layout) {
+			continue
+		}
+		if t, err := time.Parse(l.layout, s); err == nil && t.Year() >= 1900 && t.Year() <= 2200 {
+			return t, l.name, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// resolveLocalTime turns a wall clock time in loc into an instant, detecting
+// wall times that are skipped or repeated by a DST transition
+func resolveLocalTime(wall time.Time, loc *time.Location) (time.Time, *dstResolution) {
+	y, mo, d := wall.Date()
+	h, mi, s := wall.Clock()
+	asUTC := time.Date(y, mo, d, h, mi, s, wall.Nanosecond(), time.UTC)
+
+	// The offsets in effect a day before and after bound any transition nearby
+	_, before := asUTC.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := asUTC.Add(24 * time.Hour).In(loc).Zone()
+
+	var candidates []time.Time
+	for _, offset := range []int{before, after} {
+		t := asUTC.Add(-time.Duration(offset) * time.Second).In(loc)
+		if _, o := t.Zone(); o != offset {
+			continue
+		}
+		if len(candidates) > 0 && candidates[0].Equal(t) {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+
+	switch len(candidates) {
+	case 1:
+		return candidates[0], nil
+	case 2:
+		if candidates[1].Before(candidates[0]) {
+			candidates[0], candidates[1] = candidates[1], candidates[0]
+		}
+		return candidates[0], &dstResolution{
+			Status:      "ambiguous",
+			Explanation: fmt.Sprintf("%s occurs twice in %s because clocks are turned back; the first occurrence (%s) was chosen", wall.Format("2006-01-02 15:04:05"), loc, candidates[0].Format(time.RFC3339)),
+			Candidates:  []string{candidates[0].Format(time.RFC3339), candidates[1].Format(time.RFC3339)},
+		}
+	}
+
+	// Non-existent: interpret with the offset before the transition, which
+	// moves the wall clock forward by the size of the gap
+	t := asUTC.Add(-time.Duration(before) * time.Second).In(loc)
+	return t, &dstResolution{
+		Status:      "nonexistent",
+		Explanation: fmt.Sprintf("%s does not exist in %s because clocks are turned forward; it was interpreted with the previous offset as %s", wall.Format("2006-01-02 15:04:05"), loc, t.Format(time.RFC3339)),
+	}
+}
+
+// parseNaive parses a timestamp without zone information as a wall clock time
+func parseNaive(s string) (time.Time, string, bool) {
+	for _, l := range naiveLayouts {
+		if t, err := time.Parse(l.layout, s); err == nil {
+			if t.Year() == 0 {
+				// Syslog timestamps have no year; assume the current one
+				t = t.AddDate(time.Now().Year(), 0, 0)
+			}
+			return t, l.name, true
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// parseTimestamp parses s in any of the supported formats. Timestamps
+// without zone information are interpreted in loc.
+func parseTimestamp(s string, loc *time.Location) (*parsedTimestamp, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("time is required")
+	}
+
+	if wall, format, ok := parseCompactDate(s); ok {
+		t, dst := resolveLocalTime(wall, loc)
+		zone := loc.String()
+		if loc == time.Local {
+			zone = localZoneName()
+		}
+		notes := []string{fmt.Sprintf("the input has no zone information and was interpreted in %s", zone)}
+		if epoch, unit, ok := parseUnixTimestamp(s); ok {
+			notes = append(notes, fmt.Sprintf("%s is ambiguous: it was read as a %s, as %s it would be %s", s, format, unit, epoch.Format(time.RFC3339)))
+		}
+		return &parsedTimestamp{t: t, format: format, zone: zone, dst: dst, notes: notes}, nil
+	}
+
+	if unixTimestampPattern.MatchString(s) && !strings.Contains(s, "/") {
+		if t, format, ok := parseUnixTimestamp(s); ok {
+			return &parsedTimestamp{t: t, format: format, zone: "UTC", notes: []string{fmt.Sprintf("the unit was detected as %s from the number of digits", strings.TrimPrefix(format, "Unix "))}}, nil
+		}
+	}
+
+	// Trailing zone abbreviation or IANA name, e.g. "2025-03-01 14:00 PST"
+	if m := trailingZonePattern.FindStringSubmatch(s); m != nil {
+		rest, zone := m[1], m[2]
+		if wall, format, ok := parseNaive(rest); ok {
+			if offset, ok := zoneAbbreviations[strings.ToUpper(zone)]; ok {
+				p := &parsedTimestamp{
+					t:      time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), time.FixedZone(strings.ToUpper(zone), offset)),
+					format: format + " with zone abbreviation",
+					zone:   fmt.Sprintf("%s (UTC%s)", strings.ToUpper(zone), formatUTCOffset(offset)),
+				}
+				if note, ok := ambiguousAbbreviations[strings.ToUpper(zone)]; ok {
+					p.notes = append(p.notes, note)
+				}
+				return p, nil
+			}
+			if zoneLoc, err := loadLocation(zone); err == nil && strings.Contains(zone, "/") {
+				t, dst := resolveLocalTime(wall, zoneLoc)
+				return &parsedTimestamp{t: t, format: format + " with IANA zone", zone: zoneLoc.String(), dst: dst}, nil
+			}
+		}
+	}
+
+	for _, l := range zonedLayouts {
+		t, err := time.Parse(l.layout, s)
+		if err != nil {
+			continue
+		}
+
+		p := &parsedTimestamp{t: t, format: l.name}
+		name, offset := t.Zone()
+
+		// Go assigns offset 0 to abbreviations it does not know; use the table instead
+		if offset == 0 && name != "" && name != "UTC" && name != "GMT" && name != "Z" {
+			known, ok := zoneAbbreviations[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown time zone abbreviation '%s' in '%s'", name, s)
+			}
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.FixedZone(name, known))
+			p.t = t
+			offset = known
+			if note, ok := ambiguousAbbreviations[name]; ok {
+				p.notes = append(p.notes, note)
+			}
+		}
+
+		p.zone = "UTC" + formatUTCOffset(offset)
+		if name != "" && !strings.HasPrefix(name, "+") && !strings.HasPrefix(name, "-") {
+			p.zone = fmt.Sprintf("%s (UTC%s)", name, formatUTCOffset(offset))
+		}
+
+		return p, nil
+	}
+
+	if wall, format, ok := parseNaive(s); ok {
+		t, dst := resolveLocalTime(wall, loc)
+		zone := loc.String()
+		if loc == time.Local {
+			zone = localZoneName()
+		}
+		return &parsedTimestamp{
+			t:      t,
+			format: format,
+			zone:   zone,
+			dst:    dst,
+			notes:  []string{fmt.Sprintf("the input has no zone information and was interpreted in %s", zone)},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unrecognised time format '%s'", s)
+}
+
+// TimeConvert parses a timestamp and converts it into the requested time zones
+func TimeConvert(ctx context.Context, req *mcp.CallToolRequest, input timeConvertInput) (*mcp.CallToolResult, *timeConvertOutput, error) {
+	from, err := loadLocation(input.FromTimezone)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsed, err := parseTimestamp(input.Time, from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	format := input.Format
+	if format == "" {
+		format = "rfc3339"
+	}
+
+	targets := input.ToTimezones
+	if len(targets) == 0 {
+		targets = []string{"UTC", "Local"}
+	}
+
+	output := &timeConvertOutput{
+		Input:          input.Time,
+		DetectedFormat: parsed.format,
+		SourceZone:     parsed.zone,
+		UTC:            parsed.t.UTC().Format(time.RFC3339Nano),
+		Unix:           parsed.t.Unix(),
+		UnixMillis:     parsed.t.UnixMilli(),
+		DST:            parsed.dst,
+		Notes:          parsed.notes,
+		Conversions:    make([]zonedTime, 0, len(targets)),
+	}
+
+	for _, target := range targets {
+		loc, err := loadLocation(target)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		name := loc.String()
+		if loc == time.Local {
+			name = localZoneName()
+		}
+
+		output.Conversions = append(output.Conversions, describeTime(parsed.t.In(loc), name, format))
+	}
+
+	return nil, output, nil
+}
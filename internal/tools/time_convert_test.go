@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTimestamp(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	tests := []struct {
+		in     string
+		want   string
+		format string
+		dst    string
+		note   string
+	}{
+		{in: "1700000000", want: "2023-11-14T22:13:20Z", format: "Unix seconds"},
+		{in: "1700000000.5", want: "2023-11-14T22:13:20.5Z", format: "Unix seconds"},
+		{in: "-86400", want: "1969-12-31T00:00:00Z", format: "Unix seconds"},
+		{in: "1700000000123", want: "2023-11-14T22:13:20.123Z", format: "Unix milliseconds"},
+		{in: "1700000000123456", want: "2023-11-14T22:13:20.123456Z", format: "Unix microseconds"},
+		{in: "1700000000123456789", want: "2023-11-14T22:13:20.123456789Z", format: "Unix nanoseconds"},
+		{in: "20250301", want: "2025-03-01T00:00:00+01:00", format: "compact date (YYYYMMDD)", note: "ambiguous"},
+		{in: "20250301143000", want: "2025-03-01T14:30:00+01:00", format: "compact date time (YYYYMMDDhhmmss)", note: "ambiguous"},
+		{in: "20251399", want: "1970-08-23T09:23:19Z", format: "Unix seconds"},
+		{in: "2025-03-01T14:00:00+05:30", want: "2025-03-01T14:00:00+05:30", format: "RFC3339"},
+		{in: "2025-03-01 14:00 PST", want: "2025-03-01T14:00:00-08:00", format: "date time with zone abbreviation"},
+		{in: "2025-03-01 14:00 CST", want: "2025-03-01T14:00:00-06:00", format: "date time with zone abbreviation", note: "China Standard Time"},
+		{in: "2025-07-01 14:00 America/New_York", want: "2025-07-01T14:00:00-04:00", format: "date time with IANA zone"},
+		{in: "Sat, 01 Mar 2025 14:00:00 GMT", want: "2025-03-01T14:00:00Z", format: "RFC1123 with zone abbreviation"},
+		{in: "01/Mar/2025:14:00:00 +0100", want: "2025-03-01T14:00:00+01:00", format: "Common Log Format"},
+		{in: "2025-03-01 14:00", want: "2025-03-01T14:00:00+01:00", format: "date time", note: "no zone information"},
+		{in: "2025-03-30 02:30", want: "2025-03-30T03:30:00+02:00", format: "date time", dst: "nonexistent"},
+		{in: "2025-10-26 02:30", want: "2025-10-26T02:30:00+02:00", format: "date time", dst: "ambiguous"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			p, err := parseTimestamp(tt.in, berlin)
+			if err != nil {
+				t.Fatalf("parseTimestamp: %v", err)
+			}
+
+			want, _ := time.Parse(time.RFC3339Nano, tt.want)
+			if !p.t.Equal(want) {
+				t.Errorf("time = %s, want %s", p.t.Format(time.RFC3339Nano), tt.want)
+			}
+			if p.format != tt.format {
+				t.Errorf("format = %q, want %q", p.format, tt.format)
+			}
+
+			dst := ""
+			if p.dst != nil {
+				dst = p.dst.Status
+			}
+			if dst != tt.dst {
+				t.Errorf("dst = %q, want %q", dst, tt.dst)
+			}
+
+			if tt.note != "" && !strings.Contains(strings.Join(p.notes, "\n"), tt.note) {
+				t.Errorf("notes = %q, want one mentioning %q", p.notes, tt.note)
+			}
+		})
+	}
+}
+
+func TestParseTimestampErrors(t *testing.T) {
+	for _, in := range []string{"", "tomorrow-ish", "2025-03-01 14:00 XYZT"} {
+		if p, err := parseTimestamp(in, time.UTC); err == nil {
+			t.Errorf("parseTimestamp(%q) = %s, want an error", in, p.t)
+		}
+	}
+}
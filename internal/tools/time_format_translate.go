@@ -0,0 +1,635 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// timeToken is a date/time element shared by all format dialects
+type timeToken int
+
+const (
+	tokLiteral timeToken = iota
+	tokYear4
+	tokYear2
+	tokISOYear
+	tokMonth2
+	tokMonth1
+	tokMonthShort
+	tokMonthLong
+	tokDay2
+	tokDay1
+	tokDaySpace
+	tokDayOrdinal
+	tokYearDay
+	tokWeekdayShort
+	tokWeekdayLong
+	tokWeekdayNum // Sunday = 0
+	tokWeekdayISO // Monday = 1
+	tokISOWeek
+	tokHour24
+	tokHour24Short
+	tokHour12
+	tokHour12Short
+	tokMinute2
+	tokMinute1
+	tokSecond2
+	tokSecond1
+	tokFrac3
+	tokFrac6
+	tokFrac9
+	tokAMPM
+	tokAMPMLower
+	tokOffsetColon  // -07:00
+	tokOffset       // -0700
+	tokOffsetHours  // -07
+	tokOffsetZColon // Z or -07:00
+	tokOffsetZ      // Z or -0700
+	tokZoneAbbr
+	tokZoneID
+	tokUnix
+	tokUnixMillis
+)
+
+// timeTokenNames describes each token for notes
+var timeTokenNames = map[timeToken]string{
+	tokYear4: "4-digit year", tokYear2: "2-digit year", tokISOYear: "ISO week-numbering year",
+	tokMonth2: "zero-padded month", tokMonth1: "month without padding", tokMonthShort: "abbreviated month name", tokMonthLong: "full month name",
+	tokDay2: "zero-padded day", tokDay1: "day without padding", tokDaySpace: "space-padded day", tokDayOrdinal: "ordinal day (1st, 2nd)",
+	tokYearDay: "day of the year", tokWeekdayShort: "abbreviated weekday name", tokWeekdayLong: "full weekday name",
+	tokWeekdayNum: "weekday number (Sunday = 0)", tokWeekdayISO: "ISO weekday number (Monday = 1)", tokISOWeek: "ISO week number",
+	tokHour24: "zero-padded 24-hour hour", tokHour24Short: "24-hour hour without padding", tokHour12: "zero-padded 12-hour hour", tokHour12Short: "12-hour hour without padding",
+	tokMinute2: "zero-padded minute", tokMinute1: "minute without padding", tokSecond2: "zero-padded second", tokSecond1: "second without padding",
+	tokFrac3: "milliseconds", tokFrac6: "microseconds", tokFrac9: "nanoseconds", tokAMPM: "AM/PM marker", tokAMPMLower: "lowercase am/pm marker",
+	tokOffsetColon: "UTC offset with colon (-07:00)", tokOffset: "UTC offset (-0700)", tokOffsetHours: "UTC offset in hours (-07)",
+	tokOffsetZColon: "UTC offset with colon or 'Z' for UTC", tokOffsetZ: "UTC offset or 'Z' for UTC",
+	tokZoneAbbr: "time zone abbreviation", tokZoneID: "IANA time zone name", tokUnix: "Unix timestamp in seconds", tokUnixMillis: "Unix timestamp in milliseconds",
+}
+
+// timeTokenFallbacks is used when a dialect has no equivalent for a token
+var timeTokenFallbacks = map[timeToken]timeToken{
+	tokDaySpace:     tokDay1,
+	tokDayOrdinal:   tokDay1,
+	tokHour24Short:  tokHour24,
+	tokMinute1:      tokMinute2,
+	tokSecond1:      tokSecond2,
+	tokOffsetZColon: tokOffsetColon,
+	tokOffsetZ:      tokOffset,
+	tokOffsetHours:  tokOffset,
+	tokOffsetColon:  tokOffset,
+	tokZoneID:       tokZoneAbbr,
+	tokISOYear:      tokYear4,
+	tokFrac9:        tokFrac6,
+	tokFrac6:        tokFrac3,
+	tokFrac3:        tokFrac6,
+	tokUnixMillis:   tokUnix,
+	tokAMPMLower:    tokAMPM,
+	tokWeekdayISO:   tokWeekdayNum,
+	tokWeekdayNum:   tokWeekdayISO,
+}
+
+type timePattern struct {
+	text  string
+	token timeToken
+}
+
+// timeFormatDialects lists the patterns of each dialect; the first pattern of
+// a token is the one used when translating into the dialect
+var timeFormatDialects = map[string][]timePattern{
+	"go": {
+		{"2006", tokYear4}, {"06", tokYear2}, {"January", tokMonthLong}, {"Jan", tokMonthShort}, {"01", tokMonth2}, {"1", tokMonth1},
+		{"Monday", tokWeekdayLong}, {"Mon", tokWeekdayShort}, {"002", tokYearDay}, {"02", tokDay2}, {"_2", tokDaySpace}, {"2", tokDay1},
+		{"15", tokHour24}, {"03", tokHour12}, {"3", tokHour12Short}, {"04", tokMinute2}, {"4", tokMinute1}, {"05", tokSecond2}, {"5", tokSecond1},
+		{"000000000", tokFrac9}, {"000000", tokFrac6}, {"000", tokFrac3}, {"999999999", tokFrac9}, {"999999", tokFrac6}, {"999", tokFrac3},
+		{"PM", tokAMPM}, {"pm", tokAMPMLower}, {"MST", tokZoneAbbr},
+		{"Z07:00", tokOffsetZColon}, {"Z0700", tokOffsetZ}, {"-07:00", tokOffsetColon}, {"-0700", tokOffset}, {"-07", tokOffsetHours},
+	},
+	"strftime": {
+		{"%Y", tokYear4}, {"%y", tokYear2}, {"%G", tokISOYear}, {"%m", tokMonth2}, {"%-m", tokMonth1}, {"%B", tokMonthLong}, {"%b", tokMonthShort}, {"%h", tokMonthShort},
+		{"%d", tokDay2}, {"%-d", tokDay1}, {"%e", tokDaySpace}, {"%j", tokYearDay}, {"%a", tokWeekdayShort}, {"%A", tokWeekdayLong},
+		{"%w", tokWeekdayNum}, {"%u", tokWeekdayISO}, {"%V", tokISOWeek},
+		{"%H", tokHour24}, {"%-H", tokHour24Short}, {"%I", tokHour12}, {"%-I", tokHour12Short}, {"%M", tokMinute2}, {"%-M", tokMinute1}, {"%S", tokSecond2}, {"%-S", tokSecond1},
+		{"%3N", tokFrac3}, {"%6N", tokFrac6}, {"%N", tokFrac9}, {"%f", tokFrac6},
+		{"%p", tokAMPM}, {"%P", tokAMPMLower}, {"%z", tokOffset}, {"%:z", tokOffsetColon}, {"%Z", tokZoneAbbr}, {"%s", tokUnix},
+	},
+	"python": {
+		{"%Y", tokYear4}, {"%y", tokYear2}, {"%G", tokISOYear}, {"%m", tokMonth2}, {"%-m", tokMonth1}, {"%B", tokMonthLong}, {"%b", tokMonthShort},
+		{"%d", tokDay2}, {"%-d", tokDay1}, {"%j", tokYearDay}, {"%a", tokWeekdayShort}, {"%A", tokWeekdayLong},
+		{"%w", tokWeekdayNum}, {"%u", tokWeekdayISO}, {"%V", tokISOWeek},
+		{"%H", tokHour24}, {"%-H", tokHour24Short}, {"%I", tokHour12}, {"%-I", tokHour12Short}, {"%M", tokMinute2}, {"%-M", tokMinute1}, {"%S", tokSecond2}, {"%-S", tokSecond1},
+		{"%f", tokFrac6}, {"%p", tokAMPM}, {"%z", tokOffset}, {"%:z", tokOffsetColon}, {"%Z", tokZoneAbbr},
+	},
+	"java": {
+		{"yyyy", tokYear4}, {"uuuu", tokYear4}, {"yy", tokYear2}, {"uu", tokYear2}, {"YYYY", tokISOYear},
+		{"MMMM", tokMonthLong}, {"MMM", tokMonthShort}, {"MM", tokMonth2}, {"M", tokMonth1},
+		{"dd", tokDay2}, {"d", tokDay1}, {"DDD", tokYearDay}, {"EEEE", tokWeekdayLong}, {"EEE", tokWeekdayShort}, {"E", tokWeekdayShort}, {"e", tokWeekdayISO}, {"ww", tokISOWeek},
+		{"HH", tokHour24}, {"H", tokHour24Short}, {"hh", tokHour12}, {"h", tokHour12Short}, {"mm", tokMinute2}, {"m", tokMinute1}, {"ss", tokSecond2}, {"s", tokSecond1},
+		{"SSS", tokFrac3}, {"SSSSSS", tokFrac6}, {"SSSSSSSSS", tokFrac9}, {"a", tokAMPM},
+		{"XXX", tokOffsetZColon}, {"XX", tokOffsetZ}, {"xxx", tokOffsetColon}, {"xx", tokOffset}, {"x", tokOffsetHours}, {"Z", tokOffset}, {"ZZZZZ", tokOffsetZColon},
+		{"z", tokZoneAbbr}, {"VV", tokZoneID},
+	},
+	"moment": {
+		{"YYYY", tokYear4}, {"YY", tokYear2}, {"GGGG", tokISOYear}, {"MMMM", tokMonthLong}, {"MMM", tokMonthShort}, {"MM", tokMonth2}, {"M", tokMonth1},
+		{"DD", tokDay2}, {"D", tokDay1}, {"Do", tokDayOrdinal}, {"DDDD", tokYearDay},
+		{"dddd", tokWeekdayLong}, {"ddd", tokWeekdayShort}, {"d", tokWeekdayNum}, {"E", tokWeekdayISO}, {"WW", tokISOWeek},
+		{"HH", tokHour24}, {"H", tokHour24Short}, {"hh", tokHour12}, {"h", tokHour12Short}, {"mm", tokMinute2}, {"m", tokMinute1}, {"ss", tokSecond2}, {"s", tokSecond1},
+		{"SSS", tokFrac3}, {"SSSSSS", tokFrac6}, {"SSSSSSSSS", tokFrac9}, {"A", tokAMPM}, {"a", tokAMPMLower},
+		{"Z", tokOffsetColon}, {"ZZ", tokOffset}, {"z", tokZoneAbbr}, {"X", tokUnix}, {"x", tokUnixMillis},
+	},
+}
+
+// timeFormatCaveats notes tokens that only work in some implementations of a dialect
+var timeFormatCaveats = map[string]map[timeToken]string{
+	"strftime": {
+		tokMonth1: "'%-m' is a glibc/BSD extension", tokDay1: "'%-d' is a glibc/BSD extension", tokHour24Short: "'%-H' is a glibc/BSD extension",
+		tokHour12Short: "'%-I' is a glibc/BSD extension", tokMinute1: "'%-M' is a glibc/BSD extension", tokSecond1: "'%-S' is a glibc/BSD extension",
+		tokFrac3: "'%3N' is only supported by GNU date", tokFrac6: "'%6N' is only supported by GNU date", tokFrac9: "'%N' is only supported by GNU date",
+		tokAMPMLower: "'%P' is a glibc extension", tokOffsetColon: "'%:z' is only supported by GNU date", tokUnix: "'%s' is a glibc/BSD extension",
+	},
+	"python": {
+		tokMonth1: "'%-m' only works on Linux and macOS", tokDay1: "'%-d' only works on Linux and macOS", tokHour24Short: "'%-H' only works on Linux and macOS",
+		tokHour12Short: "'%-I' only works on Linux and macOS", tokMinute1: "'%-M' only works on Linux and macOS", tokSecond1: "'%-S' only works on Linux and macOS",
+		tokOffsetColon: "'%:z' requires Python 3.12 or later",
+	},
+	"java": {
+		tokISOYear: "'YYYY' is the week-based year and depends on the locale's week definition",
+		tokISOWeek: "'ww' depends on the locale's week definition",
+	},
+	"dayjs": {
+		tokDayOrdinal: "'Do' requires the AdvancedFormat plugin", tokISOYear: "'GGGG' requires the IsoWeek plugin", tokISOWeek: "'WW' requires the IsoWeek plugin",
+		tokWeekdayISO: "'E' requires the IsoWeek plugin", tokYearDay: "'DDDD' requires the DayOfYear plugin", tokZoneAbbr: "'z' requires the AdvancedFormat and Timezone plugins",
+		tokUnix: "'X' requires the AdvancedFormat plugin", tokUnixMillis: "'x' requires the AdvancedFormat plugin", tokFrac6: "day.js only has millisecond precision", tokFrac9: "day.js only has millisecond precision",
+	},
+	"moment": {
+		tokZoneAbbr: "'z' requires moment-timezone",
+	},
+}
+
+var timeFormatDialectNames = []string{"go", "strftime", "python", "java", "moment", "dayjs"}
+
+type formatElement struct {
+	token timeToken
+	text  string // literal text
+}
+
+type translateTimeFormatInput struct {
+	Format   string   `json:"format" jsonschema:"Date format pattern to translate, e.g. '2006-01-02 15:04', '%Y-%m-%d %H:%M', 'yyyy-MM-dd HH:mm' or 'YYYY-MM-DD HH:mm'"`
+	From     string   `json:"from,omitempty" jsonschema:"Dialect of the pattern: 'go', 'strftime', 'python', 'java', 'moment' or 'dayjs'. Detected when omitted"`
+	To       []string `json:"to,omitempty" jsonschema:"Dialects to translate into, defaults to all others"`
+	Timezone string   `json:"timezone,omitempty" jsonschema:"IANA time zone of the example, defaults to the server's local zone"`
+}
+
+type formatTranslation struct {
+	Dialect string   `json:"dialect" jsonschema:"Target dialect"`
+	Pattern string   `json:"pattern" jsonschema:"Translated pattern"`
+	Example string   `json:"example" jsonschema:"Current time formatted with the translated pattern"`
+	Notes   []string `json:"notes,omitempty" jsonschema:"Approximations and portability caveats"`
+}
+
+type translateTimeFormatOutput struct {
+	Format       string              `json:"format" jsonschema:"Pattern that was translated"`
+	From         string              `json:"from" jsonschema:"Dialect of the pattern"`
+	Example      string              `json:"example" jsonschema:"Current time formatted with the pattern"`
+	Timezone     string              `json:"timezone" jsonschema:"Time zone of the examples"`
+	Translations []formatTranslation `json:"translations" jsonschema:"The pattern in each target dialect"`
+	Notes        []string            `json:"notes,omitempty" jsonschema:"Notes about how the pattern was read"`
+}
+
+// normalizeTimeFormatDialect maps common aliases to dialect names
+func normalizeTimeFormatDialect(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "golang":
+		return "go"
+	case "c", "posix", "date":
+		return "strftime"
+	case "py":
+		return "python"
+	case "moment.js", "momentjs":
+		return "moment"
+	case "day.js":
+		return "dayjs"
+	}
+	return name
+}
+
+// detectTimeFormatDialect guesses the dialect of a pattern
+func detectTimeFormatDialect(format string) (string, error) {
+	switch {
+	case strings.Contains(format, "%"):
+		return "strftime", nil
+	case strings.Contains(format, "2006") || strings.Contains(format, "15:04") || strings.Contains(format, "Z07") || strings.Contains(format, "Jan 2"):
+		return "go", nil
+	case strings.Contains(format, "yy") || strings.Contains(format, "uuuu") || strings.Contains(format, "'") || strings.Contains(format, "dd"):
+		return "java", nil
+	case strings.Contains(format, "YY") || strings.Contains(format, "DD") || strings.Contains(format, "["):
+		return "moment", nil
+	}
+	return "", fmt.Errorf("could not detect the dialect of '%s', set 'from' to one of %s", format, strings.Join(timeFormatDialectNames, ", "))
+}
+
+// sortedPatterns returns the patterns of a dialect, longest first
+func sortedPatterns(dialect string) []timePattern {
+	if dialect == "dayjs" {
+		dialect = "moment"
+	}
+	patterns := append([]timePattern(nil), timeFormatDialects[dialect]...)
+	sort.SliceStable(patterns, func(i, j int) bool { return len(patterns[i].text) > len(patterns[j].text) })
+	return patterns
+}
+
+// parseTimeFormat splits a pattern into tokens and literals
+func parseTimeFormat(format, dialect string) ([]formatElement, error) {
+	var elems []formatElement
+	literal := func(s string) {
+		if n := len(elems); n > 0 && elems[n-1].token == tokLiteral {
+			elems[n-1].text += s
+			return
+		}
+		elems = append(elems, formatElement{token: tokLiteral, text: s})
+	}
+
+	patterns := sortedPatterns(dialect)
+	match := func(s string) (timePattern, bool) {
+		for _, p := range patterns {
+			if strings.HasPrefix(s, p.text) {
+				return p, true
+			}
+		}
+		return timePattern{}, false
+	}
+
+	for i := 0; i < len(format); {
+		rest := format[i:]
+
+		switch dialect {
+		case "strftime", "python":
+			if rest[0] != '%' {
+				literal(rest[:1])
+				i++
+				continue
+			}
+			if strings.HasPrefix(rest, "%%") {
+				literal("%")
+				i += 2
+				continue
+			}
+			composites := map[string]string{"%F": "%Y-%m-%d", "%T": "%H:%M:%S", "%D": "%m/%d/%y", "%R": "%H:%M", "%r": "%I:%M:%S %p", "%c": "%a %b %e %H:%M:%S %Y", "%x": "%m/%d/%y", "%X": "%H:%M:%S"}
+			if len(rest) >= 2 {
+				if expanded, ok := composites[rest[:2]]; ok {
+					sub, err := parseTimeFormat(expanded, dialect)
+					if err != nil {
+						return nil, err
+					}
+					elems = append(elems, sub...)
+					i += 2
+					continue
+				}
+			}
+			p, ok := match(rest)
+			if !ok {
+				end := min(len(rest), 3)
+				return nil, fmt.Errorf("unsupported %s directive '%s'", dialect, rest[:end])
+			}
+			elems = append(elems, formatElement{token: p.token})
+			i += len(p.text)
+
+		case "java":
+			c := rest[0]
+			if c == '\'' {
+				if strings.HasPrefix(rest, "''") {
+					literal("'")
+					i += 2
+					continue
+				}
+				end := strings.Index(rest[1:], "'")
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated quote in '%s'", format)
+				}
+				literal(strings.ReplaceAll(rest[1:end+1], "''", "'"))
+				i += end + 2
+				continue
+			}
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z') {
+				literal(rest[:1])
+				i++
+				continue
+			}
+			n := 1
+			for n < len(rest) && rest[n] == c {
+				n++
+			}
+			run := rest[:n]
+			found := false
+			for _, p := range patterns {
+				if p.text == run {
+					elems = append(elems, formatElement{token: p.token})
+					found = true
+					break
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("unsupported Java pattern '%s'", run)
+			}
+			i += n
+
+		case "moment", "dayjs":
+			if rest[0] == '[' {
+				end := strings.Index(rest, "]")
+				if end < 0 {
+					return nil, fmt.Errorf("unterminated '[' in '%s'", format)
+				}
+				literal(rest[1:end])
+				i += end + 1
+				continue
+			}
+			if p, ok := match(rest); ok {
+				elems = append(elems, formatElement{token: p.token})
+				i += len(p.text)
+				continue
+			}
+			literal(rest[:1])
+			i++
+
+		case "go":
+			// Go needs a '.' or ',' before fractional seconds, which stays a literal
+			if p, ok := match(rest); ok && (p.token < tokFrac3 || p.token > tokFrac9 || (i > 0 && (format[i-1] == '.' || format[i-1] == ','))) {
+				elems = append(elems, formatElement{token: p.token})
+				i += len(p.text)
+				continue
+			}
+			literal(rest[:1])
+			i++
+
+		default:
+			return nil, fmt.Errorf("unknown dialect '%s', expected one of %s", dialect, strings.Join(timeFormatDialectNames, ", "))
+		}
+	}
+
+	return elems, nil
+}
+
+// quoteTimeLiteral escapes literal text for a dialect
+func quoteTimeLiteral(s, dialect string) (string, string) {
+	switch dialect {
+	case "strftime", "python":
+		return strings.ReplaceAll(s, "%", "%%"), ""
+	case "java":
+		var b strings.Builder
+		inQuote := false
+		for _, r := range s {
+			isLetter := r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+			switch {
+			case r == '\'':
+				b.WriteString("''")
+			case isLetter && !inQuote:
+				b.WriteRune('\'')
+				b.WriteRune(r)
+				inQuote = true
+			case !isLetter && inQuote:
+				b.WriteRune('\'')
+				b.WriteRune(r)
+				inQuote = false
+			default:
+				b.WriteRune(r)
+			}
+		}
+		if inQuote {
+			b.WriteRune('\'')
+		}
+		return b.String(), ""
+	case "moment", "dayjs":
+		if strings.IndexFunc(s, func(r rune) bool { return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' }) >= 0 {
+			return "[" + s + "]", ""
+		}
+		return s, ""
+	case "go":
+		if elems, _ := parseTimeFormat(s, "go"); len(elems) != 1 || elems[0].token != tokLiteral {
+			return s, fmt.Sprintf("the literal text '%s' contains Go layout elements and cannot be escaped in a Go layout", s)
+		}
+	}
+	return s, ""
+}
+
+// renderTimeFormat writes the elements as a pattern of the dialect and
+// returns the elements actually used after approximations
+func renderTimeFormat(elems []formatElement, dialect string) (string, []formatElement, []string) {
+	table := timeFormatDialects[dialect]
+	if dialect == "dayjs" {
+		table = timeFormatDialects["moment"]
+	}
+	find := func(tok timeToken) (string, bool) {
+		for _, p := range table {
+			if p.token == tok {
+				return p.text, true
+			}
+		}
+		return "", false
+	}
+
+	var b strings.Builder
+	var used []formatElement
+	var notes []string
+	addNote := func(note string) {
+		for _, n := range notes {
+			if n == note {
+				return
+			}
+		}
+		notes = append(notes, note)
+	}
+
+	for i, e := range elems {
+		if e.token == tokLiteral {
+			text, note := quoteTimeLiteral(e.text, dialect)
+			if note != "" {
+				addNote(note)
+			}
+			b.WriteString(text)
+			used = append(used, e)
+			continue
+		}
+
+		tok := e.token
+		text, ok := find(tok)
+		for seen := map[timeToken]bool{}; !ok && !seen[tok]; {
+			seen[tok] = true
+			fallback, has := timeTokenFallbacks[tok]
+			if !has {
+				break
+			}
+			tok = fallback
+			text, ok = find(tok)
+		}
+		if !ok {
+			addNote(fmt.Sprintf("%s has no equivalent in %s and was dropped", timeTokenNames[e.token], dialect))
+			continue
+		}
+		if tok != e.token {
+			addNote(fmt.Sprintf("%s has no equivalent in %s; approximated with %s", timeTokenNames[e.token], dialect, timeTokenNames[tok]))
+		}
+		if caveat, ok := timeFormatCaveats[dialect][tok]; ok {
+			addNote(caveat)
+		}
+
+		if dialect == "go" && tok >= tokFrac3 && tok <= tokFrac9 {
+			if prev := b.String(); i == 0 || !(strings.HasSuffix(prev, ".") || strings.HasSuffix(prev, ",")) {
+				addNote("Go layouts need '.' or ',' before fractional seconds; a '.' was added")
+				b.WriteByte('.')
+				used = append(used, formatElement{token: tokLiteral, text: "."})
+			}
+		}
+
+		b.WriteString(text)
+		used = append(used, formatElement{token: tok})
+	}
+
+	return b.String(), used, notes
+}
+
+// ordinalSuffix returns the English ordinal suffix of n
+func ordinalSuffix(n int) string {
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
+	}
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
+
+// formatWithElements formats t according to parsed elements
+func formatWithElements(t time.Time, elems []formatElement, zone string) string {
+	var b strings.Builder
+	for _, e := range elems {
+		switch e.token {
+		case tokLiteral:
+			b.WriteString(e.text)
+		case tokDayOrdinal:
+			b.WriteString(strconv.Itoa(t.Day()) + ordinalSuffix(t.Day()))
+		case tokWeekdayISO:
+			b.WriteString(strconv.Itoa((int(t.Weekday())+6)%7 + 1))
+		case tokWeekdayNum:
+			b.WriteString(strconv.Itoa(int(t.Weekday())))
+		case tokISOYear:
+			year, _ := t.ISOWeek()
+			b.WriteString(strconv.Itoa(year))
+		case tokISOWeek:
+			_, week := t.ISOWeek()
+			fmt.Fprintf(&b, "%02d", week)
+		case tokHour24Short:
+			b.WriteString(strconv.Itoa(t.Hour()))
+		case tokMinute1:
+			b.WriteString(strconv.Itoa(t.Minute()))
+		case tokSecond1:
+			b.WriteString(strconv.Itoa(t.Second()))
+		case tokFrac3:
+			fmt.Fprintf(&b, "%03d", t.Nanosecond()/1e6)
+		case tokFrac6:
+			fmt.Fprintf(&b, "%06d", t.Nanosecond()/1e3)
+		case tokFrac9:
+			fmt.Fprintf(&b, "%09d", t.Nanosecond())
+		case tokZoneID:
+			b.WriteString(zone)
+		case tokUnix:
+			b.WriteString(strconv.FormatInt(t.Unix(), 10))
+		case tokUnixMillis:
+			b.WriteString(strconv.FormatInt(t.UnixMilli(), 10))
+		default:
+			for _, p := range timeFormatDialects["go"] {
+				if p.token == e.token {
+					b.WriteString(t.Format(p.text))
+					break
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// TranslateTimeFormat converts a date format pattern between Go, strftime, Python, Java and moment.js/day.js
+func TranslateTimeFormat(ctx context.Context, req *mcp.CallToolRequest, input translateTimeFormatInput) (*mcp.CallToolResult, *translateTimeFormatOutput, error) {
+	if input.Format == "" {
+		return nil, nil, fmt.Errorf("format is required")
+	}
+
+	from := normalizeTimeFormatDialect(input.From)
+
+	var notes []string
+	if from == "" {
+		detected, err := detectTimeFormatDialect(input.Format)
+		if err != nil {
+			return nil, nil, err
+		}
+		from = detected
+		notes = append(notes, fmt.Sprintf("the pattern was detected as %s; set 'from' if that is wrong", from))
+	}
+
+	elems, err := parseTimeFormat(input.Format, from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	targets := input.To
+	if len(targets) == 0 {
+		for _, d := range timeFormatDialectNames {
+			if d != from {
+				targets = append(targets, d)
+			}
+		}
+	}
+
+	// Render the examples from the same clock reading get_current_time reports
+	zone := input.Timezone
+	if zone == "" {
+		zone = "Local"
+	}
+	_, current, err := GetCurrentTime(ctx, req, currentTimeInput{Timezones: []string{zone}, Format: "rfc3339nano"})
+	if err != nil {
+		return nil, nil, err
+	}
+	now, err := time.Parse(time.RFC3339Nano, current.Times[0].Formatted)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read the current time: %w", err)
+	}
+	loc, _ := loadLocation(zone)
+	now = now.In(loc)
+	zoneName := current.Times[0].Timezone
+
+	output := &translateTimeFormatOutput{
+		Format:       input.Format,
+		From:         from,
+		Example:      formatWithElements(now, elems, zoneName),
+		Timezone:     zoneName,
+		Translations: []formatTranslation{},
+		Notes:        notes,
+	}
+
+	for _, target := range targets {
+		target = normalizeTimeFormatDialect(target)
+		if _, ok := timeFormatDialects[target]; !ok && target != "dayjs" {
+			return nil, nil, fmt.Errorf("unknown dialect '%s', expected one of %s", target, strings.Join(timeFormatDialectNames, ", "))
+		}
+
+		pattern, used, notes := renderTimeFormat(elems, target)
+		output.Translations = append(output.Translations, formatTranslation{
+			Dialect: target,
+			Pattern: pattern,
+			Example: formatWithElements(now, used, zoneName),
+			Notes:   notes,
+		})
+	}
+
+	return nil, output, nil
+}
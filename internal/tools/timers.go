@@ -0,0 +1,345 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// timerURIPrefix is the scheme of the resources describing timers. The URIs
+// are timer://<session key>/<name>: subscriptions and update notifications are
+// shared by the whole server, so the random per-session key keeps a timer
+// firing in one session from notifying another session using the same name.
+const timerURIPrefix = "timer://"
+
+var timerNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+type startTimerInput struct {
+	Name     string `json:"name" jsonschema:"Name of the timer or stopwatch (letters, digits, '.', '_' and '-')"`
+	Kind     string `json:"kind,omitempty" jsonschema:"'timer' counts down and fires, 'stopwatch' counts up. Defaults to 'timer' when a duration or time is given"`
+	Duration string `json:"duration,omitempty" jsonschema:"Countdown length, e.g. '25m', 'PT1H' or '2 hours 30 minutes'"`
+	At       string `json:"at,omitempty" jsonschema:"Fire at this time instead of after a duration, in any format accepted by time_convert"`
+	Timezone string `json:"timezone,omitempty" jsonschema:"IANA time zone 'at' is interpreted in, defaults to the server's local zone"`
+	Message  string `json:"message,omitempty" jsonschema:"Reminder text sent when the timer fires"`
+	Repeat   bool   `json:"repeat,omitempty" jsonschema:"Restart the countdown every time it fires"`
+}
+
+type timerNameInput struct {
+	Name string `json:"name" jsonschema:"Name of the timer or stopwatch"`
+}
+
+type listTimersInput struct{}
+
+type timerInfo struct {
+	Name             string     `json:"name" jsonschema:"Name of the timer"`
+	Kind             string     `json:"kind" jsonschema:"'timer' or 'stopwatch'"`
+	URI              string     `json:"uri" jsonschema:"Resource URI that receives update notifications"`
+	Message          string     `json:"message,omitempty" jsonschema:"Reminder text"`
+	Status           string     `json:"status" jsonschema:"'running', 'fired' or 'cancelled'"`
+	StartedAt        time.Time  `json:"started_at" jsonschema:"When the timer was started"`
+	FireAt           *time.Time `json:"fire_at,omitempty" jsonschema:"When the timer fires next"`
+	LastFiredAt      *time.Time `json:"last_fired_at,omitempty" jsonschema:"When the timer last fired"`
+	Repeat           bool       `json:"repeat" jsonschema:"Whether the timer restarts after firing"`
+	FireCount        int        `json:"fire_count" jsonschema:"Number of times the timer has fired"`
+	ElapsedSeconds   float64    `json:"elapsed_seconds" jsonschema:"Seconds since the timer was started"`
+	RemainingSeconds *float64   `json:"remaining_seconds,omitempty" jsonschema:"Seconds until the timer fires"`
+	Elapsed          string     `json:"elapsed" jsonschema:"Elapsed time, e.g. '1h2m3s'"`
+}
+
+type listTimersOutput struct {
+	Timers []timerInfo `json:"timers" jsonschema:"Timers and stopwatches of this session"`
+}
+
+// sessionTimer is a countdown timer or stopwatch owned by a client session
+type sessionTimer struct {
+	name      string
+	uri       string
+	kind      string
+	message   string
+	repeat    bool
+	interval  time.Duration
+	startedAt time.Time
+	fireAt    time.Time
+	lastFired time.Time
+	fireCount int
+	stopped   bool
+	timer     *time.Timer
+}
+
+// info returns the state of the timer. The caller must hold the registry lock.
+func (t *sessionTimer) info(now time.Time) timerInfo {
+	elapsed := now.Sub(t.startedAt)
+	info := timerInfo{
+		Name:           t.name,
+		Kind:           t.kind,
+		URI:            t.uri,
+		Message:        t.message,
+		Status:         "running",
+		StartedAt:      t.startedAt,
+		Repeat:         t.repeat,
+		FireCount:      t.fireCount,
+		ElapsedSeconds: elapsed.Seconds(),
+		Elapsed:        elapsed.Round(time.Second).String(),
+	}
+
+	if t.kind == "timer" {
+		if t.fireCount > 0 && !t.repeat {
+			info.Status = "fired"
+		} else {
+			fireAt := t.fireAt
+			remaining := fireAt.Sub(now).Seconds()
+			info.FireAt = &fireAt
+			info.RemainingSeconds = &remaining
+		}
+		if t.fireCount > 0 {
+			lastFired := t.lastFired
+			info.LastFiredAt = &lastFired
+		}
+	}
+	if t.stopped {
+		info.Status = "cancelled"
+	}
+
+	return info
+}
+
+// Timers keeps the timers and stopwatches of each client session and notifies
+// subscribers of timer:// resources when a timer fires
+type Timers struct {
+	server   *mcp.Server
+	mu       sync.Mutex
+	sessions map[*mcp.ServerSession]map[string]*sessionTimer
+	// keys holds the random key of each session used in its timer:// URIs
+	keys map[*mcp.ServerSession]string
+}
+
+// NewTimers creates the timer registry for a server
+func NewTimers(server *mcp.Server) *Timers {
+	return &Timers{
+		server:   server,
+		sessions: make(map[*mcp.ServerSession]map[string]*sessionTimer),
+		keys:     make(map[*mcp.ServerSession]string),
+	}
+}
+
+// sessionTimers returns the timers and URI key of a session, dropping them
+// once the session closes. The caller must hold the lock.
+func (r *Timers) sessionTimers(ss *mcp.ServerSession) (map[string]*sessionTimer, string, error) {
+	timers, ok := r.sessions[ss]
+	if !ok {
+		buf := make([]byte, 8)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, "", fmt.Errorf("failed to create session key: %w", err)
+		}
+		timers = make(map[string]*sessionTimer)
+		r.sessions[ss] = timers
+		r.keys[ss] = hex.EncodeToString(buf)
+
+		go func() {
+			_ = ss.Wait()
+
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			for _, t := range r.sessions[ss] {
+				if t.timer != nil {
+					t.timer.Stop()
+				}
+			}
+			delete(r.sessions, ss)
+			delete(r.keys, ss)
+		}()
+	}
+	return timers, r.keys[ss], nil
+}
+
+// fire handles a timer going off
+func (r *Timers) fire(ss *mcp.ServerSession, t *sessionTimer) {
+	r.mu.Lock()
+	if t.stopped {
+		r.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	t.fireCount++
+	t.lastFired = now
+	if t.repeat {
+		t.fireAt = now.Add(t.interval)
+		t.timer.Reset(t.interval)
+	}
+	data := map[string]any{
+		"event":    "timer_fired",
+		"timer":    t.name,
+		"uri":      t.uri,
+		"fired_at": now.Format(time.RFC3339),
+		"count":    t.fireCount,
+	}
+	if t.message != "" {
+		data["message"] = t.message
+	}
+	r.mu.Unlock()
+
+	ctx := context.Background()
+	if err := ss.Log(ctx, &mcp.LoggingMessageParams{Level: "notice", Logger: "timers", Data: data}); err != nil {
+		log.Printf("failed to send timer notification: %v", err)
+	}
+	if err := r.server.ResourceUpdated(ctx, &mcp.ResourceUpdatedNotificationParams{URI: t.uri}); err != nil {
+		log.Printf("failed to send resource update: %v", err)
+	}
+}
+
+// Start starts a named countdown timer or stopwatch
+func (r *Timers) Start(ctx context.Context, req *mcp.CallToolRequest, input startTimerInput) (*mcp.CallToolResult, *timerInfo, error) {
+	if !timerNamePattern.MatchString(input.Name) {
+		return nil, nil, fmt.Errorf("invalid timer name '%s', use 1-64 letters, digits, '.', '_' or '-'", input.Name)
+	}
+
+	kind := strings.ToLower(input.Kind)
+	if kind == "" {
+		kind = "stopwatch"
+		if input.Duration != "" || input.At != "" {
+			kind = "timer"
+		}
+	}
+
+	now := time.Now()
+	t := &sessionTimer{name: input.Name, kind: kind, message: input.Message, startedAt: now}
+
+	switch kind {
+	case "stopwatch":
+		if input.Duration != "" || input.At != "" || input.Repeat {
+			return nil, nil, fmt.Errorf("stopwatches count up and take no duration, time or repeat")
+		}
+	case "timer":
+		switch {
+		case input.Duration != "" && input.At != "":
+			return nil, nil, fmt.Errorf("specify either duration or at, not both")
+		case input.Duration != "":
+			d, err := parseCalendarDuration(input.Duration)
+			if err != nil {
+				return nil, nil, err
+			}
+			if d.Years != 0 || d.Months != 0 {
+				return nil, nil, fmt.Errorf("timer durations cannot use years or months, use 'at' for a calendar date")
+			}
+			t.interval = time.Duration(d.Days)*24*time.Hour + d.Clock
+		case input.At != "":
+			loc, err := loadLocation(input.Timezone)
+			if err != nil {
+				return nil, nil, err
+			}
+			at, err := parseDateArg(input.At, loc, now)
+			if err != nil {
+				return nil, nil, err
+			}
+			t.interval = at.Sub(now)
+		default:
+			return nil, nil, fmt.Errorf("timers need a duration or a time to fire at")
+		}
+		if t.interval <= 0 {
+			return nil, nil, fmt.Errorf("the timer would fire in the past")
+		}
+		if input.Repeat && t.interval < time.Second {
+			return nil, nil, fmt.Errorf("repeating timers need an interval of at least one second")
+		}
+		t.repeat = input.Repeat
+		t.fireAt = now.Add(t.interval)
+	default:
+		return nil, nil, fmt.Errorf("unknown kind '%s', expected 'timer' or 'stopwatch'", input.Kind)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timers, key, err := r.sessionTimers(req.Session)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing, ok := timers[t.name]; ok && (existing.kind == "stopwatch" || existing.repeat || existing.fireCount == 0) {
+		return nil, nil, fmt.Errorf("a %s named '%s' already exists, cancel it first", existing.kind, t.name)
+	}
+	t.uri = timerURIPrefix + key + "/" + t.name
+	timers[t.name] = t
+
+	if kind == "timer" {
+		ss := req.Session
+		t.timer = time.AfterFunc(t.interval, func() { r.fire(ss, t) })
+	}
+
+	info := t.info(now)
+	return nil, &info, nil
+}
+
+// List lists the timers and stopwatches of the session
+func (r *Timers) List(ctx context.Context, req *mcp.CallToolRequest, input listTimersInput) (*mcp.CallToolResult, *listTimersOutput, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	output := &listTimersOutput{Timers: []timerInfo{}}
+	for _, t := range r.sessions[req.Session] {
+		output.Timers = append(output.Timers, t.info(now))
+	}
+	sort.Slice(output.Timers, func(i, j int) bool { return output.Timers[i].Name < output.Timers[j].Name })
+
+	return nil, output, nil
+}
+
+// Cancel stops a timer or stopwatch and returns its final state
+func (r *Timers) Cancel(ctx context.Context, req *mcp.CallToolRequest, input timerNameInput) (*mcp.CallToolResult, *timerInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.sessions[req.Session][input.Name]
+	if !ok {
+		return nil, nil, fmt.Errorf("no timer or stopwatch named '%s'", input.Name)
+	}
+
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+	info := t.info(time.Now())
+	info.Status = "cancelled"
+	t.stopped = true
+	delete(r.sessions[req.Session], input.Name)
+
+	return nil, &info, nil
+}
+
+// ReadResource returns the state of the timer named by a timer:// URI.
+// Timers of other sessions are reported as not found.
+func (r *Timers) ReadResource(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	key, name, _ := strings.Cut(strings.TrimPrefix(uri, timerURIPrefix), "/")
+
+	r.mu.Lock()
+	t, ok := r.sessions[req.Session][name]
+	ok = ok && r.keys[req.Session] == key
+	var info timerInfo
+	if ok {
+		info = t.info(time.Now())
+	}
+	r.mu.Unlock()
+
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode timer: %w", err)
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{{URI: uri, MIMEType: "application/json", Text: string(data)}},
+	}, nil
+}
@@ -0,0 +1,748 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	trashInfoHeader   = "[Trash Info]"
+	trashInfoSuffix   = ".trashinfo"
+	trashDateLayout   = "2006-01-02T15:04:05"
+	trashPreviewTTL   = 15 * time.Minute
+	maxTrashNameTries = 1000
+)
+
+// trashPreviews holds dry-run results that may be executed with their preview_id
+var trashPreviews = struct {
+	sync.Mutex
+	m map[string]trashPreview
+}{m: make(map[string]trashPreview)}
+
+type trashPreview struct {
+	paths   []string
+	created time.Time
+}
+
+type trashFilesInput struct {
+	Paths     []string `json:"paths,omitempty" jsonschema:"Files or directories to move to the trash; a dry run is always done first"`
+	PreviewID string   `json:"preview_id,omitempty" jsonschema:"ID returned by a dry run; moves exactly the previewed paths to the trash after confirmation"`
+}
+
+type trashItem struct {
+	Path        string `json:"path" jsonschema:"Original path"`
+	IsDir       bool   `json:"is_dir" jsonschema:"Whether the path is a directory"`
+	Size        int64  `json:"size" jsonschema:"Size in bytes, including directory contents"`
+	TrashDir    string `json:"trash_dir,omitempty" jsonschema:"Trash directory the item goes to"`
+	TrashedName string `json:"trashed_name,omitempty" jsonschema:"Name of the item inside the trash"`
+	Error       string `json:"error,omitempty" jsonschema:"Why the item cannot be or was not trashed"`
+}
+
+type trashFilesOutput struct {
+	DryRun    bool        `json:"dry_run" jsonschema:"Whether this was only a preview"`
+	PreviewID string      `json:"preview_id,omitempty" jsonschema:"Pass this back as preview_id to move the previewed items to the trash"`
+	ExpiresAt *time.Time  `json:"expires_at,omitempty" jsonschema:"When the preview expires"`
+	Cancelled bool        `json:"cancelled" jsonschema:"Whether the user declined the confirmation"`
+	Items     []trashItem `json:"items" jsonschema:"Items and where they go"`
+	Trashed   int         `json:"trashed" jsonschema:"Number of items moved to the trash"`
+	TotalSize int64       `json:"total_size" jsonschema:"Combined size of the items in bytes"`
+}
+
+type listTrashInput struct {
+	Filter string `json:"filter,omitempty" jsonschema:"Only list items whose original path contains this text or matches this glob"`
+}
+
+type trashEntry struct {
+	Name         string    `json:"name" jsonschema:"Name inside the trash, used to restore the item"`
+	OriginalPath string    `json:"original_path" jsonschema:"Where the item was deleted from"`
+	DeletedAt    time.Time `json:"deleted_at" jsonschema:"When the item was moved to the trash"`
+	IsDir        bool      `json:"is_dir" jsonschema:"Whether the item is a directory"`
+	Size         int64     `json:"size" jsonschema:"Size in bytes, including directory contents"`
+	TrashDir     string    `json:"trash_dir" jsonschema:"Trash directory holding the item"`
+}
+
+type listTrashOutput struct {
+	TrashDirs []string     `json:"trash_dirs" jsonschema:"Trash directories that were read"`
+	Items     []trashEntry `json:"items" jsonschema:"Items in the trash, most recently deleted first"`
+	TotalSize int64        `json:"total_size" jsonschema:"Combined size of the listed items in bytes"`
+}
+
+type restoreFromTrashInput struct {
+	Items     []string `json:"items" jsonschema:"Names inside the trash (from list_trash) or original paths of the items to restore"`
+	Overwrite bool     `json:"overwrite,omitempty" jsonschema:"Move files that now exist at the original location to the trash and restore in their place, after confirmation"`
+}
+
+type restoredItem struct {
+	Item         string `json:"item" jsonschema:"Requested item"`
+	OriginalPath string `json:"original_path,omitempty" jsonschema:"Where the item was restored to"`
+	Restored     bool   `json:"restored" jsonschema:"Whether the item was restored"`
+	Replaced     string `json:"replaced,omitempty" jsonschema:"Name inside the trash of the existing item that was moved aside"`
+	Error        string `json:"error,omitempty" jsonschema:"Why the item was not restored"`
+}
+
+type restoreFromTrashOutput struct {
+	Cancelled bool           `json:"cancelled" jsonschema:"Whether the user declined replacing existing files"`
+	Items     []restoredItem `json:"items" jsonschema:"Result for each requested item"`
+	Restored  int            `json:"restored" jsonschema:"Number of restored items"`
+}
+
+// checkTrashSupported reports an error on systems without a freedesktop.org trash
+func checkTrashSupported() error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("the trash tools are not supported on %s", runtime.GOOS)
+	}
+	return nil
+}
+
+// homeTrashDir returns $XDG_DATA_HOME/Trash
+func homeTrashDir() (string, error) {
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" || !filepath.IsAbs(data) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get user home directory: %w", err)
+		}
+		data = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(data, "Trash"), nil
+}
+
+// trashTopDir returns the mount point a trash directory belongs to, or "" for the home trash
+func trashTopDir(trashDir, homeTrash string) string {
+	if trashDir == homeTrash {
+		return ""
+	}
+	if strings.HasPrefix(filepath.Base(trashDir), ".Trash-") {
+		return filepath.Dir(trashDir)
+	}
+	// $topdir/.Trash/$uid
+	return filepath.Dir(filepath.Dir(trashDir))
+}
+
+// trashDirCandidates lists the trash directories for a path in the order of the
+// freedesktop.org specification: the home trash for files on the same mount,
+// otherwise $topdir/.Trash/$uid (if the admin created a sticky .Trash) and then
+// $topdir/.Trash-$uid. It has no side effects, so dry runs can use it.
+func trashDirCandidates(path, homeTrash string, mounts []mountInfo) []string {
+	fileMount, ok := mountForPath(mounts, path)
+	if !ok {
+		return []string{homeTrash}
+	}
+	if homeMount, ok := mountForPath(mounts, homeTrash); ok && homeMount.MountPoint == fileMount.MountPoint {
+		return []string{homeTrash}
+	}
+
+	top := fileMount.MountPoint
+	uid := strconv.Itoa(os.Getuid())
+
+	var dirs []string
+	if info, err := os.Lstat(filepath.Join(top, ".Trash")); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		dirs = append(dirs, filepath.Join(top, ".Trash", uid))
+	}
+	return append(dirs, filepath.Join(top, ".Trash-"+uid))
+}
+
+// trashDirFor picks the trash directory for a path from trashDirCandidates,
+// creating it when needed
+func trashDirFor(path, homeTrash string, mounts []mountInfo) (string, error) {
+	if err := os.MkdirAll(homeTrash, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create home trash: %w", err)
+	}
+
+	candidates := trashDirCandidates(path, homeTrash, mounts)
+	if candidates[0] == homeTrash {
+		return homeTrash, nil
+	}
+
+	// $topdir/.Trash/$uid is skipped if it cannot be created
+	for _, dir := range candidates[:len(candidates)-1] {
+		if err := os.MkdirAll(dir, 0o700); err == nil {
+			return dir, nil
+		}
+	}
+
+	dir := candidates[len(candidates)-1]
+	top := trashTopDir(dir, homeTrash)
+	if info, err := os.Lstat(dir); err == nil && (!info.IsDir() || info.Mode()&os.ModeSymlink != 0) {
+		return "", fmt.Errorf("%s exists but is not a usable trash directory", dir)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create trash directory on %s: %w", top, err)
+	}
+
+	return dir, nil
+}
+
+// pathSize returns the size of a file or the total size of a directory tree
+func pathSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// checkTrashable rejects paths that must never be moved to the trash
+func checkTrashable(path, homeTrash string, mounts []mountInfo) error {
+	home, _ := os.UserHomeDir()
+	switch {
+	case path == "/" || path == home:
+		return fmt.Errorf("refusing to trash %s", path)
+	case path == homeTrash || strings.HasPrefix(path, homeTrash+"/"):
+		return fmt.Errorf("%s is already in the trash", path)
+	case strings.Contains(path, "/.Trash-") || strings.Contains(path, "/.Trash/"):
+		return fmt.Errorf("%s is inside a trash directory", path)
+	}
+	for _, m := range mounts {
+		if m.MountPoint == path {
+			return fmt.Errorf("%s is a mount point", path)
+		}
+	}
+	return nil
+}
+
+// resolveTrashPath makes a path absolute the way it is recorded in trash info
+// files. Symlinks are trashed themselves, so only the directories leading to the
+// path are resolved.
+func resolveTrashPath(p string) (string, error) {
+	path, err := expandHome(p)
+	if err == nil {
+		path, err = filepath.Abs(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	if dir, err := filepath.EvalSymlinks(filepath.Dir(path)); err == nil {
+		path = filepath.Join(dir, filepath.Base(path))
+	}
+	return path, nil
+}
+
+// planTrash resolves and validates the paths of a trash request
+func planTrash(paths []string, homeTrash string, mounts []mountInfo) ([]trashItem, []string) {
+	items := make([]trashItem, 0, len(paths))
+	resolved := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		item := trashItem{Path: p}
+
+		path, err := resolveTrashPath(p)
+		if err != nil {
+			item.Error = err.Error()
+			items = append(items, item)
+			continue
+		}
+		item.Path = path
+
+		info, err := os.Lstat(path)
+		if err != nil {
+			item.Error = err.Error()
+			items = append(items, item)
+			continue
+		}
+		item.IsDir = info.IsDir()
+		item.Size = info.Size()
+
+		if err := checkTrashable(path, homeTrash, mounts); err != nil {
+			item.Error = err.Error()
+			items = append(items, item)
+			continue
+		}
+		if item.IsDir {
+			item.Size = pathSize(path)
+		}
+
+		item.TrashDir = trashDirCandidates(path, homeTrash, mounts)[0]
+
+		items = append(items, item)
+		resolved = append(resolved, path)
+	}
+
+	return items, resolved
+}
+
+// moveToTrash moves one path into a trash directory and writes its .trashinfo file
+func moveToTrash(path, trashDir, homeTrash string) (string, error) {
+	filesDir := filepath.Join(trashDir, "files")
+	infoDir := filepath.Join(trashDir, "info")
+	if err := os.MkdirAll(filesDir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filesDir, err)
+	}
+	if err := os.MkdirAll(infoDir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", infoDir, err)
+	}
+
+	// The home trash records absolute paths, per-mount trashes paths relative to the mount
+	recorded := path
+	if top := trashTopDir(trashDir, homeTrash); top != "" {
+		if rel, err := filepath.Rel(top, path); err == nil && !strings.HasPrefix(rel, "..") {
+			recorded = rel
+		}
+	}
+	info := fmt.Sprintf("%s\nPath=%s\nDeletionDate=%s\n", trashInfoHeader, (&url.URL{Path: recorded}).EscapedPath(), time.Now().Format(trashDateLayout))
+
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+
+	// Reserve a unique name by creating the info file exclusively
+	for i := 1; i <= maxTrashNameTries; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s.%d%s", stem, i, ext)
+		}
+
+		infoPath := filepath.Join(infoDir, name+trashInfoSuffix)
+		f, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, fs.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to write trash info: %w", err)
+		}
+		if _, err := f.WriteString(info); err != nil {
+			f.Close()
+			os.Remove(infoPath)
+			return "", fmt.Errorf("failed to write trash info: %w", err)
+		}
+		f.Close()
+
+		if _, err := os.Lstat(filepath.Join(filesDir, name)); err == nil {
+			os.Remove(infoPath)
+			continue
+		}
+
+		if err := os.Rename(path, filepath.Join(filesDir, name)); err != nil {
+			os.Remove(infoPath)
+			return "", fmt.Errorf("failed to move %s to the trash: %w", path, err)
+		}
+		return name, nil
+	}
+
+	return "", fmt.Errorf("failed to find a free name in %s", filesDir)
+}
+
+// TrashFiles moves files to the freedesktop.org trash after a dry run and confirmation
+func TrashFiles(ctx context.Context, req *mcp.CallToolRequest, input trashFilesInput) (*mcp.CallToolResult, *trashFilesOutput, error) {
+	if err := checkTrashSupported(); err != nil {
+		return nil, nil, err
+	}
+
+	homeTrash, err := homeTrashDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	mounts, err := readMountInfo(procRoot)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+
+	trashPreviews.Lock()
+	for id, p := range trashPreviews.m {
+		if now.Sub(p.created) > trashPreviewTTL {
+			delete(trashPreviews.m, id)
+		}
+	}
+	preview, havePreview := trashPreviews.m[input.PreviewID]
+	trashPreviews.Unlock()
+
+	// Without a valid preview ID this is always a dry run
+	if input.PreviewID == "" {
+		if len(input.Paths) == 0 {
+			return nil, nil, fmt.Errorf("paths is required")
+		}
+
+		items, resolved := planTrash(input.Paths, homeTrash, mounts)
+		output := &trashFilesOutput{DryRun: true, Items: items}
+		for _, item := range items {
+			if item.Error == "" {
+				output.TotalSize += item.Size
+			}
+		}
+
+		if len(resolved) > 0 {
+			buf := make([]byte, 8)
+			if _, err := rand.Read(buf); err != nil {
+				return nil, nil, fmt.Errorf("failed to create preview id: %w", err)
+			}
+			output.PreviewID = hex.EncodeToString(buf)
+			expires := now.Add(trashPreviewTTL)
+			output.ExpiresAt = &expires
+
+			trashPreviews.Lock()
+			trashPreviews.m[output.PreviewID] = trashPreview{paths: resolved, created: now}
+			trashPreviews.Unlock()
+		}
+
+		return nil, output, nil
+	}
+
+	if !havePreview {
+		return nil, nil, fmt.Errorf("unknown or expired preview_id '%s', run a dry run again", input.PreviewID)
+	}
+	if len(input.Paths) > 0 {
+		return nil, nil, fmt.Errorf("pass either paths for a dry run or preview_id to execute it, not both")
+	}
+
+	items, resolved := planTrash(preview.paths, homeTrash, mounts)
+	output := &trashFilesOutput{Items: items}
+	for _, item := range items {
+		if item.Error == "" {
+			output.TotalSize += item.Size
+		}
+	}
+
+	if len(resolved) == 0 {
+		return nil, output, nil
+	}
+
+	confirmed, err := confirmAction(ctx, req, fmt.Sprintf("Move %d item(s) (%d bytes) to the trash?", len(resolved), output.TotalSize))
+	if err != nil {
+		return nil, nil, err
+	}
+	if !confirmed {
+		output.Cancelled = true
+		return nil, output, nil
+	}
+
+	trashPreviews.Lock()
+	delete(trashPreviews.m, input.PreviewID)
+	trashPreviews.Unlock()
+
+	for i := range output.Items {
+		item := &output.Items[i]
+		if item.Error != "" {
+			continue
+		}
+
+		dir, err := trashDirFor(item.Path, homeTrash, mounts)
+		if err != nil {
+			item.Error = err.Error()
+			continue
+		}
+		item.TrashDir = dir
+
+		name, err := moveToTrash(item.Path, dir, homeTrash)
+		if err != nil {
+			item.Error = err.Error()
+			continue
+		}
+		item.TrashedName = name
+		output.Trashed++
+	}
+
+	return nil, output, nil
+}
+
+// trashDirs returns the home trash and the existing per-mount trashes of the user
+func trashDirs() ([]string, string, error) {
+	homeTrash, err := homeTrashDir()
+	if err != nil {
+		return nil, "", err
+	}
+	dirs := []string{homeTrash}
+
+	mounts, err := readMountInfo(procRoot)
+	if err != nil {
+		return nil, "", err
+	}
+
+	uid := strconv.Itoa(os.Getuid())
+	seen := map[string]bool{homeTrash: true}
+	for _, m := range mounts {
+		for _, dir := range []string{filepath.Join(m.MountPoint, ".Trash", uid), filepath.Join(m.MountPoint, ".Trash-"+uid)} {
+			if seen[dir] {
+				continue
+			}
+			seen[dir] = true
+			if info, err := os.Stat(filepath.Join(dir, "info")); err == nil && info.IsDir() {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+
+	return dirs, homeTrash, nil
+}
+
+// readTrashInfo parses a .trashinfo file
+func readTrashInfo(path, topDir string) (string, time.Time, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	defer f.Close()
+
+	var original string
+	var deleted time.Time
+	inSection := false
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inSection = line == trashInfoHeader
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Path":
+			if decoded, err := url.PathUnescape(value); err == nil {
+				original = decoded
+			} else {
+				original = value
+			}
+		case "DeletionDate":
+			deleted, _ = time.ParseInLocation(trashDateLayout, value, time.Local)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", time.Time{}, err
+	}
+
+	if original == "" {
+		return "", time.Time{}, fmt.Errorf("%s has no Path entry", path)
+	}
+	if !filepath.IsAbs(original) && topDir != "" {
+		original = filepath.Join(topDir, original)
+	}
+
+	return original, deleted, nil
+}
+
+// readTrashEntries lists the items of all trash directories
+func readTrashEntries() ([]trashEntry, []string, error) {
+	dirs, homeTrash, err := trashDirs()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries := []trashEntry{}
+	for _, dir := range dirs {
+		infos, err := os.ReadDir(filepath.Join(dir, "info"))
+		if err != nil {
+			continue
+		}
+
+		for _, info := range infos {
+			name, ok := strings.CutSuffix(info.Name(), trashInfoSuffix)
+			if !ok {
+				continue
+			}
+
+			original, deleted, err := readTrashInfo(filepath.Join(dir, "info", info.Name()), trashTopDir(dir, homeTrash))
+			if err != nil {
+				continue
+			}
+
+			entry := trashEntry{Name: name, OriginalPath: original, DeletedAt: deleted, TrashDir: dir}
+			if st, err := os.Lstat(filepath.Join(dir, "files", name)); err == nil {
+				entry.IsDir = st.IsDir()
+				entry.Size = st.Size()
+				if entry.IsDir {
+					entry.Size = pathSize(filepath.Join(dir, "files", name))
+				}
+			} else {
+				continue
+			}
+
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].DeletedAt.After(entries[j].DeletedAt) })
+
+	return entries, dirs, nil
+}
+
+// ListTrash lists the items in the user's trash directories
+func ListTrash(ctx context.Context, req *mcp.CallToolRequest, input listTrashInput) (*mcp.CallToolResult, *listTrashOutput, error) {
+	if err := checkTrashSupported(); err != nil {
+		return nil, nil, err
+	}
+
+	entries, dirs, err := readTrashEntries()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &listTrashOutput{TrashDirs: dirs, Items: []trashEntry{}}
+	for _, e := range entries {
+		if input.Filter != "" && !strings.Contains(e.OriginalPath, input.Filter) {
+			if ok, _ := filepath.Match(input.Filter, e.OriginalPath); !ok {
+				if ok, _ := filepath.Match(input.Filter, filepath.Base(e.OriginalPath)); !ok {
+					continue
+				}
+			}
+		}
+		output.Items = append(output.Items, e)
+		output.TotalSize += e.Size
+	}
+
+	return nil, output, nil
+}
+
+// RestoreFromTrash moves items from the trash back to where they were deleted from
+func RestoreFromTrash(ctx context.Context, req *mcp.CallToolRequest, input restoreFromTrashInput) (*mcp.CallToolResult, *restoreFromTrashOutput, error) {
+	if err := checkTrashSupported(); err != nil {
+		return nil, nil, err
+	}
+	if len(input.Items) == 0 {
+		return nil, nil, fmt.Errorf("items is required")
+	}
+
+	entries, _, err := readTrashEntries()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := &restoreFromTrashOutput{Items: []restoredItem{}}
+	matches := make([]*trashEntry, len(input.Items))
+	targets := make(map[string]bool)
+	var replaced []string
+
+	for n, item := range input.Items {
+		result := restoredItem{Item: item}
+
+		// Match trash names first, then the most recently deleted item with that original path
+		var match *trashEntry
+		for i := range entries {
+			if entries[i].Name == item {
+				match = &entries[i]
+				break
+			}
+		}
+		if match == nil {
+			target, err := resolveTrashPath(item)
+			for i := range entries {
+				if err == nil && entries[i].OriginalPath == target {
+					match = &entries[i]
+					break
+				}
+			}
+		}
+
+		switch {
+		case match == nil:
+			result.Error = "not found in the trash"
+		case targets[match.OriginalPath]:
+			result.OriginalPath = match.OriginalPath
+			result.Error = "another requested item is restored to the same location"
+		default:
+			result.OriginalPath = match.OriginalPath
+			targets[match.OriginalPath] = true
+			matches[n] = match
+
+			if _, err := os.Lstat(match.OriginalPath); err == nil {
+				if input.Overwrite {
+					replaced = append(replaced, match.OriginalPath)
+				} else {
+					result.Error = "a file already exists at the original location; set overwrite to move it to the trash and restore in its place"
+					matches[n] = nil
+				}
+			}
+		}
+
+		output.Items = append(output.Items, result)
+	}
+
+	var homeTrash string
+	var mounts []mountInfo
+
+	// Existing files are never deleted, they swap places with the restored items
+	if len(replaced) > 0 {
+		homeTrash, err = homeTrashDir()
+		if err != nil {
+			return nil, nil, err
+		}
+		mounts, err = readMountInfo(procRoot)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		confirmed, err := confirmAction(ctx, req, fmt.Sprintf("Move %d existing item(s) to the trash and restore the trashed versions in their place?\n\n%s", len(replaced), strings.Join(replaced, "\n")))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !confirmed {
+			output.Cancelled = true
+			return nil, output, nil
+		}
+	}
+
+	for n, match := range matches {
+		if match == nil {
+			continue
+		}
+		result := &output.Items[n]
+
+		if _, err := os.Lstat(match.OriginalPath); err == nil {
+			// Only replace what the user confirmed
+			if !slices.Contains(replaced, match.OriginalPath) {
+				result.Error = "a file appeared at the original location, run the restore again"
+				continue
+			}
+			if err := checkTrashable(match.OriginalPath, homeTrash, mounts); err != nil {
+				result.Error = fmt.Sprintf("cannot move the existing item aside: %v", err)
+				continue
+			}
+			dir, err := trashDirFor(match.OriginalPath, homeTrash, mounts)
+			if err != nil {
+				result.Error = fmt.Sprintf("cannot move the existing item aside: %v", err)
+				continue
+			}
+			name, err := moveToTrash(match.OriginalPath, dir, homeTrash)
+			if err != nil {
+				result.Error = fmt.Sprintf("cannot move the existing item aside: %v", err)
+				continue
+			}
+			result.Replaced = name
+		}
+
+		if err := os.MkdirAll(filepath.Dir(match.OriginalPath), 0o755); err != nil {
+			result.Error = fmt.Sprintf("failed to recreate parent directory: %v", err)
+			continue
+		}
+
+		if err := os.Rename(filepath.Join(match.TrashDir, "files", match.Name), match.OriginalPath); err != nil {
+			result.Error = fmt.Sprintf("failed to restore: %v", err)
+			continue
+		}
+		os.Remove(filepath.Join(match.TrashDir, "info", match.Name+trashInfoSuffix))
+
+		result.Restored = true
+		output.Restored++
+	}
+
+	return nil, output, nil
+}
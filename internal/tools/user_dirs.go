@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// userDirKinds lists the well-known user directories with their XDG key,
+// their macOS folder name and their Windows "User Shell Folders" value
+var userDirKinds = []struct {
+	name, xdgKey, macName, windowsValue string
+}{
+	{"Desktop", "XDG_DESKTOP_DIR", "Desktop", "Desktop"},
+	{"Documents", "XDG_DOCUMENTS_DIR", "Documents", "Personal"},
+	{"Downloads", "XDG_DOWNLOAD_DIR", "Downloads", "{374DE290-123F-4565-9164-39C4925E467B}"},
+	{"Music", "XDG_MUSIC_DIR", "Music", "My Music"},
+	{"Pictures", "XDG_PICTURES_DIR", "Pictures", "My Pictures"},
+	{"Videos", "XDG_VIDEOS_DIR", "Movies", "My Video"},
+	{"Templates", "XDG_TEMPLATES_DIR", "", "Templates"},
+	{"Public", "XDG_PUBLICSHARE_DIR", "Public", ""},
+}
+
+type listUserDirectoriesInput struct{}
+
+type userDirectory struct {
+	Name   string `json:"name" jsonschema:"Directory kind, e.g. 'Downloads'"`
+	Path   string `json:"path" jsonschema:"Resolved path"`
+	Source string `json:"source" jsonschema:"Where the path came from: environment variable, user-dirs.dirs, registry or default"`
+	Exists bool   `json:"exists" jsonschema:"Whether the directory exists"`
+}
+
+type listUserDirectoriesOutput struct {
+	System      string          `json:"system" jsonschema:"Operating system of the server"`
+	Home        string          `json:"home" jsonschema:"User's home directory"`
+	ConfigFile  string          `json:"config_file,omitempty" jsonschema:"user-dirs.dirs file that was read"`
+	Directories []userDirectory `json:"directories" jsonschema:"Resolved user directories"`
+}
+
+// userDirsConfigPath returns the location of the XDG user-dirs.dirs file
+func userDirsConfigPath(home string) string {
+	config := os.Getenv("XDG_CONFIG_HOME")
+	if config == "" || !filepath.IsAbs(config) {
+		config = filepath.Join(home, ".config")
+	}
+	return filepath.Join(config, "user-dirs.dirs")
+}
+
+// parseUserDirsValue expands a user-dirs.dirs value, which is either
+// "$HOME/relative" or an absolute path
+func parseUserDirsValue(value, home string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if unquoted, ok := strings.CutPrefix(value, `"`); ok {
+		unquoted, ok = strings.CutSuffix(unquoted, `"`)
+		if !ok {
+			return "", false
+		}
+		var b strings.Builder
+		for i := 0; i < len(unquoted); i++ {
+			if unquoted[i] == '\\' && i+1 < len(unquoted) {
+				i++
+			}
+			b.WriteByte(unquoted[i])
+		}
+		value = b.String()
+	}
+
+	switch {
+	case value == "$HOME" || value == "$HOME/":
+		// Pointing a directory at $HOME disables it; xdg-user-dir reports the home directory
+		return home, true
+	case strings.HasPrefix(value, "$HOME/"):
+		return filepath.Join(home, value[len("$HOME/"):]), true
+	case filepath.IsAbs(value):
+		return filepath.Clean(value), true
+	}
+	return "", false
+}
+
+// readUserDirsFile parses a user-dirs.dirs file into XDG keys and paths
+func readUserDirsFile(path, home string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dirs := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if dir, ok := parseUserDirsValue(value, home); ok {
+			dirs[strings.TrimSpace(key)] = dir
+		}
+	}
+
+	return dirs, scanner.Err()
+}
+
+// resolveUserDirs resolves the well-known user directories for the current OS
+func resolveUserDirs() (*listUserDirectoriesOutput, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user home directory: %w", err)
+	}
+
+	output := &listUserDirectoriesOutput{System: runtime.GOOS, Home: home, Directories: []userDirectory{}}
+
+	var xdgDirs map[string]string
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" {
+		configPath := userDirsConfigPath(home)
+		if dirs, err := readUserDirsFile(configPath, home); err == nil {
+			xdgDirs = dirs
+			output.ConfigFile = configPath
+		}
+	}
+
+	for _, kind := range userDirKinds {
+		dir := userDirectory{Name: kind.name}
+
+		switch runtime.GOOS {
+		case "windows":
+			if path, ok := windowsShellFolder(kind.windowsValue); ok {
+				dir.Path, dir.Source = path, "registry"
+			} else if kind.name == "Public" && os.Getenv("PUBLIC") != "" {
+				dir.Path, dir.Source = os.Getenv("PUBLIC"), "environment variable PUBLIC"
+			} else {
+				dir.Path, dir.Source = filepath.Join(home, kind.name), "default"
+			}
+		case "darwin":
+			if kind.macName == "" {
+				continue
+			}
+			dir.Path, dir.Source = filepath.Join(home, kind.macName), "default"
+		default:
+			if env, ok := parseUserDirsValue(os.Getenv(kind.xdgKey), home); ok {
+				dir.Path, dir.Source = env, "environment variable "+kind.xdgKey
+			} else if path, ok := xdgDirs[kind.xdgKey]; ok {
+				dir.Path, dir.Source = path, "user-dirs.dirs"
+			} else {
+				dir.Path, dir.Source = filepath.Join(home, kind.name), "default"
+			}
+		}
+
+		if info, err := os.Stat(dir.Path); err == nil && info.IsDir() {
+			dir.Exists = true
+		}
+		output.Directories = append(output.Directories, dir)
+	}
+
+	return output, nil
+}
+
+// userDir returns the path of a well-known user directory such as "Downloads"
+func userDir(name string) (string, error) {
+	dirs, err := resolveUserDirs()
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range dirs.Directories {
+		if dir.Name == name {
+			return dir.Path, nil
+		}
+	}
+	return "", fmt.Errorf("no %s directory on %s", name, runtime.GOOS)
+}
+
+// ListUserDirectories lists the user's Desktop, Documents, Downloads and other well-known directories
+func ListUserDirectories(ctx context.Context, req *mcp.CallToolRequest, input listUserDirectoriesInput) (*mcp.CallToolResult, *listUserDirectoriesOutput, error) {
+	output, err := resolveUserDirs()
+	if err != nil {
+		return nil, nil, err
+	}
+	return nil, output, nil
+}
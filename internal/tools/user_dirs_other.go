@@ -0,0 +1,8 @@
+//go:build !windows
+
+package tools
+
+// windowsShellFolder is only available on Windows
+func windowsShellFolder(value string) (string, bool) {
+	return "", false
+}
@@ -0,0 +1,47 @@
+//go:build windows
+
+package tools
+
+import (
+	"os"
+	"regexp"
+	"syscall"
+	"unsafe"
+)
+
+var windowsEnvPattern = regexp.MustCompile(`%([^%]+)%`)
+
+// windowsShellFolder reads a folder location from the user's "User Shell Folders" registry key
+func windowsShellFolder(value string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+
+	keyPath, _ := syscall.UTF16PtrFromString(`Software\Microsoft\Windows\CurrentVersion\Explorer\User Shell Folders`)
+	var key syscall.Handle
+	if err := syscall.RegOpenKeyEx(syscall.HKEY_CURRENT_USER, keyPath, 0, syscall.KEY_READ, &key); err != nil {
+		return "", false
+	}
+	defer syscall.RegCloseKey(key)
+
+	name, _ := syscall.UTF16PtrFromString(value)
+	var typ, size uint32
+	if err := syscall.RegQueryValueEx(key, name, nil, &typ, nil, &size); err != nil || size == 0 {
+		return "", false
+	}
+
+	buf := make([]uint16, size/2+1)
+	if err := syscall.RegQueryValueEx(key, name, nil, &typ, (*byte)(unsafe.Pointer(&buf[0])), &size); err != nil {
+		return "", false
+	}
+
+	// Values are usually REG_EXPAND_SZ such as %USERPROFILE%\Downloads
+	path := windowsEnvPattern.ReplaceAllStringFunc(syscall.UTF16ToString(buf), func(m string) string {
+		if v := os.Getenv(m[1 : len(m)-1]); v != "" {
+			return v
+		}
+		return m
+	})
+
+	return path, path != ""
+}